@@ -8,6 +8,7 @@ import (
 	"syscall"
 
 	"github.com/ksysoev/mcp-go-tools/pkg/cmd"
+	_ "github.com/ksysoev/mcp-go-tools/pkg/repo/memory" // registers repository.type: memory
 )
 
 // version is the version of the application. It should be set at build time.
@@ -15,7 +16,9 @@ var version = "dev"
 var build = "dev"
 
 func main() {
-	ctx, _ := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	// SIGINT and SIGTERM cancel the context, which stops the server; the server
+	// command then logs its final line and flushes the log file before returning.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 
 	rootCmd, err := cmd.InitCommands(build, version)
 	if err != nil {
@@ -23,7 +26,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
+	err = rootCmd.ExecuteContext(ctx)
+
+	stop()
+
+	if err != nil {
 		slog.Error("failed to execute command", slog.Any("error", err))
 		os.Exit(1)
 	}
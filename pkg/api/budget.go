@@ -0,0 +1,67 @@
+package api
+
+import (
+	"strings"
+)
+
+// bytesPerToken approximates the size of a token for English text and code.
+const bytesPerToken = 4
+
+// contextShare is the inverse of the share of a model context window a response
+// may take, leaving room for the conversation and the generated code.
+const contextShare = 4
+
+// modelContextWindows maps model name prefixes to their context windows in tokens.
+var modelContextWindows = map[string]int{
+	"gpt-3.5":     16_385,
+	"gpt-4":       8_192,
+	"gpt-4-32k":   32_768,
+	"gpt-4-turbo": 128_000,
+	"gpt-4o":      128_000,
+	"gpt-4.1":     1_047_576,
+	"o1":          200_000,
+	"o3":          200_000,
+	"o4-mini":     200_000,
+	"claude":      200_000,
+	"gemini":      1_048_576,
+	"llama":       128_000,
+	"mistral":     32_000,
+}
+
+// modelTokenBudget returns the response budget in tokens for the named model: a
+// quarter of the context window of the longest matching name prefix, compared
+// case-insensitively. Unknown models get defaultBudget, and an empty name has
+// no budget.
+func modelTokenBudget(model string, defaultBudget int) int {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if model == "" {
+		return 0
+	}
+
+	window, matched := 0, ""
+
+	for prefix, size := range modelContextWindows {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(matched) {
+			window, matched = size, prefix
+		}
+	}
+
+	if matched == "" {
+		return defaultBudget
+	}
+
+	return window / contextShare
+}
+
+// responseLimit returns the response size cap in bytes: the smaller of the
+// configured MaxResponseBytes and tokenBudget converted to bytes, ignoring
+// either when zero. Zero means responses are not capped.
+func (s *Service) responseLimit(tokenBudget int) int {
+	limit := s.config.MaxResponseBytes
+
+	if budget := tokenBudget * bytesPerToken; budget > 0 && (limit <= 0 || budget < limit) {
+		limit = budget
+	}
+
+	return limit
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelTokenBudget(t *testing.T) {
+	tests := []struct {
+		model string
+		want  int
+	}{
+		{model: "", want: 0},
+		{model: "gpt-4", want: 2_048},
+		{model: "gpt-4-32k-0613", want: 8_192},
+		{model: "GPT-4o-mini", want: 32_000},
+		{model: "claude-sonnet-4-20250514", want: 50_000},
+		{model: "unknown-model", want: 1_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			assert.Equal(t, tt.want, modelTokenBudget(tt.model, 1_000))
+		})
+	}
+}
+
+func TestService_responseLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxBytes    int
+		tokenBudget int
+		want        int
+	}{
+		{name: "uncapped", want: 0},
+		{name: "config only", maxBytes: 1_000, want: 1_000},
+		{name: "budget only", tokenBudget: 100, want: 400},
+		{name: "budget tighter", maxBytes: 1_000, tokenBudget: 100, want: 400},
+		{name: "config tighter", maxBytes: 300, tokenBudget: 100, want: 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(&Config{MaxResponseBytes: tt.maxBytes}, NewMockToolHandler(t), ServerInfo{})
+
+			assert.Equal(t, tt.want, svc.responseLimit(tt.tokenBudget))
+		})
+	}
+}
+
+func TestService_handleCodeStyle_Model(t *testing.T) {
+	rules := []core.Rule{
+		{Name: "rule1", Category: "code", Description: strings.Repeat("a", 50)},
+		{Name: "rule2", Category: "code", Description: strings.Repeat("b", 500)},
+	}
+
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{}).Return(rules, nil)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, []string{"code"}, core.Filter{}, rules[:1]).Once()
+
+	svc := New(&Config{DefaultTokenBudget: 50}, handler, ServerInfo{})
+
+	resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{Model: "unknown-model"}})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Content, 2)
+
+	text := resp.Content[0].TextContent.Text
+	assert.LessOrEqual(t, len(text), 200)
+	assert.Contains(t, text, "[Response truncated: 1 more rules omitted")
+	assert.JSONEq(t, `{"categories":["code"],"matched":1,"truncated":true}`, resp.Content[1].TextContent.Text)
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// categoryToolDescription is the description template of per-category tools,
+// filled in with the category name and its summary.
+const categoryToolDescription = `Retrieve Go coding style guidelines from the %q category: %s.
+
+This tool is pre-scoped to its category; use it instead of codestyle when you already know the rules you need are in this category. Nested categories below it are included.
+
+Input Parameters:
+- exclude_categories: Optional comma separated list of nested categories whose rules are never returned
+- format: Optional output format, "text" (default) or "markdown"
+- code_contains: Optional substring to match against example code (ignoring case and accents)
+- language: Optional language of the rules; when it has no matching rules, the next language of the server fallback chain is tried
+- min_results: Optional minimum number of rules wanted (0 means no minimum)
+- widen_on_insufficient: Optional flag to drop code_contains and query again when fewer than min_results rules match
+- per_category_limit: Optional maximum number of rules per category (0 means unlimited)
+- max_examples_per_rule: Optional maximum number of examples per rule (0 means the server default)
+- min_severity: Optional minimum rule severity, one of "must", "should", "may"
+- include_deprecated: Optional flag to include deprecated rules, which are excluded by default
+- include_examples: Optional flag to include or omit code examples, overriding the server default
+- include_category: Optional flag to precede each rule with a "Category:" line in text output
+- names_only: Optional flag to return only the names and categories of the matching rules, one "name (category)" per line
+- with_examples_only: Optional flag to return only rules that have code examples
+- updated_since: Optional RFC3339 time, only rules updated at or after it are returned
+- sort: Optional order of rules, "canonical" (default), "updated", "severity", "name", "manual" or "popularity"
+- client: Optional client identifier, used to serve a client-specific subset of rules
+- model: Optional name of the client model; the response is capped to a share of its context window
+
+Returns the same content as the codestyle tool: the matching rules followed by a JSON metadata block.
+`
+
+// categorySummaries describe the top-level categories in per-category tool descriptions.
+var categorySummaries = map[string]string{
+	"documentation": "rules for comments, package docs, and godoc",
+	"testing":       "testing conventions, table tests, benchmarks",
+	"code":          "code organization, naming, interfaces, error handling, concurrency",
+	"template":      "template for go application structure",
+}
+
+// Category documents a declared rule category, see Config.Categories.
+type Category struct {
+	// Name of the category, e.g. "testing" or "code/concurrency"
+	Name string
+	// DisplayName is an optional human-readable name, e.g. "Testing"
+	DisplayName string
+	// Description optionally summarizes the rules in the category
+	Description string
+}
+
+// CategoryToolArgs holds the parameters of a per-category tool, which are those
+// of the codestyle tool without categories.
+type CategoryToolArgs struct {
+	// IncludeCategory overrides whether text output names the category of each rule
+	IncludeCategory *bool `json:"include_category,omitempty" jsonschema:"description=Precede each rule with a 'Category:' line in text output. Defaults to false"`
+	// ExcludeCategories lists categories whose rules are dropped
+	ExcludeCategories string `json:"exclude_categories" jsonschema:"description=Comma-separated list of nested categories whose rules are never returned (e.g. 'code/concurrency' for the code tool)"`
+	FilterArgs
+}
+
+// UnmarshalJSON decodes per-category tool arguments after checking them against the tool input schema.
+func (a *CategoryToolArgs) UnmarshalJSON(data []byte) error {
+	type plain CategoryToolArgs
+	return unmarshalArgs(data, reflect.TypeFor[CategoryToolArgs](), (*plain)(a))
+}
+
+// codeStyleArgs returns the equivalent codestyle arguments scoped to category.
+func (a *CategoryToolArgs) codeStyleArgs(category string) CodeStyleArgs {
+	return CodeStyleArgs{
+		Categories:        category,
+		ExcludeCategories: a.ExcludeCategories,
+		IncludeCategory:   a.IncludeCategory,
+		FilterArgs:        a.FilterArgs,
+	}
+}
+
+// categoryToolName returns the name of the tool serving category, e.g. "get_testing_rules"
+// for "testing" and "get_code_concurrency_rules" for "code/concurrency".
+func categoryToolName(category string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+
+		return '_'
+	}, strings.ToLower(category))
+
+	return "get_" + name + "_rules"
+}
+
+// declaredCategory returns the declared metadata of category, if it is declared.
+func (s *Service) declaredCategory(category string) (Category, bool) {
+	i := slices.IndexFunc(s.config.Categories, func(c Category) bool { return c.Name == category })
+	if i < 0 {
+		return Category{}, false
+	}
+
+	return s.config.Categories[i], true
+}
+
+// categorySummary returns the description of category used in tool and resource descriptions:
+// its declared description, or else the summary of its built-in top-level category, naming
+// the subtree for nested categories.
+func (s *Service) categorySummary(category string) string {
+	if declared, ok := s.declaredCategory(category); ok && declared.Description != "" {
+		return declared.Description
+	}
+
+	root, sub, nested := strings.Cut(category, core.CategorySeparator)
+	if nested {
+		return fmt.Sprintf("%s rules on %s", root, strings.ReplaceAll(sub, core.CategorySeparator, " "))
+	}
+
+	if summary, ok := categorySummaries[root]; ok {
+		return summary
+	}
+
+	return root + " rules"
+}
+
+// setupCategoryTools registers a tool for each configured category tool, serving the
+// codestyle tool scoped to that category. Categories outside the allowed categories get no tool.
+// Returns error wrapping ErrInvalidCategory if a configured category is not nested in a
+// known category, see Service.knownCategories, or is the "*" wildcard, or if registration
+// or the repository stats fail.
+func (s *Service) setupCategoryTools(ctx context.Context, server *mcp.Server) error {
+	for _, category := range s.config.CategoryTools {
+		if category == core.AllCategories {
+			return fmt.Errorf("%w for category tool: %s", ErrInvalidCategory, category)
+		}
+
+		known, err := s.knownCategories(ctx, []string{category})
+		if err != nil {
+			return err
+		}
+
+		if !known[categoryRoot(category)] {
+			return fmt.Errorf("%w for category tool: %s", ErrInvalidCategory, category)
+		}
+
+		if _, err := s.restrictCategories([]string{category}); errors.Is(err, ErrCategoryNotAllowed) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		name := categoryToolName(category)
+		description := fmt.Sprintf(categoryToolDescription, category, s.categorySummary(category))
+
+		if err := server.RegisterTool(name, description, wrapTool(name, s.categoryTool(category), s.middlewares)); err != nil {
+			return fmt.Errorf("register %s tool: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// categoryTool returns the handler of the tool serving category.
+func (s *Service) categoryTool(category string) toolHandlerFunc[CategoryToolArgs] {
+	return func(ctx context.Context, args CategoryToolArgs) (*mcp.ToolResponse, error) {
+		return s.handleCodeStyle(ctx, args.codeStyleArgs(category))
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	mcp "github.com/metoro-io/mcp-golang"
+	"github.com/metoro-io/mcp-golang/transport/stdio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryToolName(t *testing.T) {
+	assert.Equal(t, "get_testing_rules", categoryToolName("testing"))
+	assert.Equal(t, "get_code_concurrency_rules", categoryToolName("code/concurrency"))
+}
+
+func TestService_categorySummary(t *testing.T) {
+	svc := New(&Config{Categories: []Category{
+		{Name: "security", DisplayName: "Security", Description: "Secure coding practices"},
+		{Name: "code/concurrency", DisplayName: "Concurrency"},
+	}}, NewMockToolHandler(t), ServerInfo{})
+
+	assert.Equal(t, categorySummaries["testing"], svc.categorySummary("testing"))
+	assert.Equal(t, "code rules on errors sentinel", svc.categorySummary("code/errors/sentinel"))
+	assert.Equal(t, "Secure coding practices", svc.categorySummary("security"))
+	assert.Equal(t, "code rules on concurrency", svc.categorySummary("code/concurrency"), "declared categories without description")
+	assert.Equal(t, "performance rules", svc.categorySummary("performance"))
+}
+
+func TestService_setupCategoryTools(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []string
+		wantErr    bool
+	}{
+		{
+			name:       "valid categories",
+			categories: []string{"testing", "documentation", "code/concurrency"},
+		},
+		{
+			name:       "repository category",
+			categories: []string{"security", "security/crypto"},
+		},
+		{
+			name:       "unknown category",
+			categories: []string{"unknown"},
+			wantErr:    true,
+		},
+		{
+			name:       "wildcard",
+			categories: []string{core.AllCategories},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().Stats(mock.Anything).Return(core.RepoStats{RulesPerCategory: map[string]int{"security/crypto": 1}}, nil).Maybe()
+
+			svc := New(&Config{CategoryTools: tt.categories}, handler, ServerInfo{})
+
+			err := svc.setupTools(context.Background(), mcp.NewServer(stdio.NewStdioServerTransport()))
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCategory)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_setupCategoryTools_AllowedCategories(t *testing.T) {
+	svc := New(&Config{CategoryTools: []string{"testing", "code", "code/concurrency"}, AllowedCategories: []string{"code/concurrency"}}, NewMockToolHandler(t), ServerInfo{})
+
+	send := serve(t, svc, func(server *mcp.Server) error {
+		return svc.setupCategoryTools(context.Background(), server)
+	})
+
+	var resp struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(send(`{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{}}`)), &resp))
+
+	names := make([]string, 0, len(resp.Result.Tools))
+	for _, tool := range resp.Result.Tools {
+		names = append(names, tool.Name)
+	}
+
+	// code is narrowed down to its allowed descendant, testing is not allowed
+	assert.ElementsMatch(t, []string{"get_code_rules", "get_code_concurrency_rules"}, names)
+}
+
+func TestService_setupCategoryTools_DeclaredCategories(t *testing.T) {
+	categories := []Category{{Name: "security", DisplayName: "Security", Description: "Secure coding practices"}}
+	svc := New(&Config{CategoryTools: []string{"security", "testing"}, Categories: categories}, NewMockToolHandler(t), ServerInfo{})
+
+	send := serve(t, svc, func(server *mcp.Server) error {
+		return svc.setupCategoryTools(context.Background(), server)
+	})
+
+	var resp struct {
+		Result struct {
+			Tools []struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(send(`{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{}}`)), &resp))
+
+	descriptions := make(map[string]string, len(resp.Result.Tools))
+	for _, tool := range resp.Result.Tools {
+		descriptions[tool.Name] = tool.Description
+	}
+
+	assert.Contains(t, descriptions["get_security_rules"], "Secure coding practices")
+	assert.Contains(t, descriptions["get_testing_rules"], categorySummaries["testing"])
+}
+
+func TestService_categoryTool(t *testing.T) {
+	rules := []core.Rule{
+		{Name: "table_tests", Category: "testing", Description: "Use table driven tests"},
+	}
+
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"testing"}, core.Filter{MinSeverity: "must", ExcludeCategories: []string{"testing/unit"}}).Return(rules, nil)
+
+	svc := New(&Config{CategoryTools: []string{"testing"}}, handler, ServerInfo{})
+
+	resp, err := svc.categoryTool("testing")(context.Background(), CategoryToolArgs{ExcludeCategories: "testing/unit", FilterArgs: FilterArgs{MinSeverity: "must"}})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Content, 2)
+	assert.Contains(t, resp.Content[0].TextContent.Text, "Use table driven tests")
+	assert.JSONEq(t, `{"categories":["testing"],"matched":1,"truncated":false}`, resp.Content[1].TextContent.Text)
+}
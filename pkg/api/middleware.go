@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// ErrUnexpectedArgs is returned when a middleware replaces tool arguments with a value of another type.
+var ErrUnexpectedArgs = errors.New("unexpected tool arguments type")
+
+// ErrBusy is returned by the ConcurrencyLimit middleware when too many tool calls are running.
+var ErrBusy = errors.New("server busy, retry later")
+
+// loggerKey is the context key for the request-scoped logger.
+type loggerKey struct{}
+
+// toolHandlerFunc is the signature of MCP tool handlers registered by the service.
+type toolHandlerFunc[T any] func(ctx context.Context, args T) (*mcp.ToolResponse, error)
+
+// ToolRequest describes a single tool call as seen by middlewares.
+type ToolRequest struct {
+	// Args holds the decoded tool arguments, e.g. CodeStyleArgs for the codestyle tool.
+	// Middlewares may replace it, but must keep its concrete type.
+	Args any
+	// Tool is the name of the called tool
+	Tool string
+}
+
+// Handler processes a tool call.
+type Handler func(ctx context.Context, req *ToolRequest) (*mcp.ToolResponse, error)
+
+// Middleware wraps a Handler to run custom logic, such as authentication,
+// auditing or response transformation, around every tool call.
+type Middleware func(next Handler) Handler
+
+// DefaultMiddlewares returns the middlewares applied when none are passed to New.
+func DefaultMiddlewares() []Middleware {
+	return []Middleware{RequestLogging}
+}
+
+// chain wraps h with middlewares so that the first middleware is the outermost one.
+func chain(h Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+
+	return h
+}
+
+// wrapTool adapts a typed tool handler to the Handler chain and back, so that
+// middlewares can be applied to it while registration keeps the typed arguments
+// needed for the tool schema.
+func wrapTool[T any](tool string, next toolHandlerFunc[T], middlewares []Middleware) toolHandlerFunc[T] {
+	h := chain(func(ctx context.Context, req *ToolRequest) (*mcp.ToolResponse, error) {
+		args, ok := req.Args.(T)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T for tool %s", ErrUnexpectedArgs, req.Args, req.Tool)
+		}
+
+		return next(ctx, args)
+	}, middlewares)
+
+	return func(ctx context.Context, args T) (*mcp.ToolResponse, error) {
+		return h(ctx, &ToolRequest{Tool: tool, Args: args})
+	}
+}
+
+// RequestLogging is a middleware that gives each call a generated correlation ID.
+// The ID is attached to a request-scoped logger stored in the context, and the
+// start and end of the call are logged with its duration.
+func RequestLogging(next Handler) Handler {
+	return func(ctx context.Context, req *ToolRequest) (*mcp.ToolResponse, error) {
+		logger := slog.Default().With(
+			slog.String("request_id", newRequestID()),
+			slog.String("tool", req.Tool),
+		)
+
+		ctx = context.WithValue(ctx, loggerKey{}, logger)
+		start := time.Now()
+
+		logger.DebugContext(ctx, "tool request started")
+
+		resp, err := next(ctx, req)
+
+		logger.DebugContext(ctx, "tool request finished",
+			slog.Duration("duration", time.Since(start)),
+			slog.Bool("success", err == nil),
+		)
+
+		return resp, err
+	}
+}
+
+// ConcurrencyLimit returns a middleware letting at most limit tool calls run at once,
+// across all the tools it wraps. Calls beyond the limit fail immediately with ErrBusy,
+// so that clients retry later instead of piling up on a slow repository.
+// A limit of zero or less disables the limit.
+func ConcurrencyLimit(limit int) Middleware {
+	if limit <= 0 {
+		return func(next Handler) Handler { return next }
+	}
+
+	sem := make(chan struct{}, limit)
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *ToolRequest) (*mcp.ToolResponse, error) {
+			select {
+			case sem <- struct{}{}:
+			default:
+				return nil, fmt.Errorf("%w: %d tool calls already running", ErrBusy, limit)
+			}
+
+			defer func() { <-sem }()
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// loggerFromContext returns the request-scoped logger, or the default logger
+// if the context does not carry one.
+func loggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+// newRequestID generates a random 16-character hexadecimal correlation ID.
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error
+
+	return hex.EncodeToString(b)
+}
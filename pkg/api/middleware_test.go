@@ -0,0 +1,273 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	mcp "github.com/metoro-io/mcp-golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogging(t *testing.T) {
+	var buf bytes.Buffer
+
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	handler := RequestLogging(func(ctx context.Context, req *ToolRequest) (*mcp.ToolResponse, error) {
+		loggerFromContext(ctx).Info("inside handler", slog.Any("args", req.Args))
+		return mcp.NewToolResponse(mcp.NewTextContent("payload")), assert.AnError
+	})
+
+	resp, err := handler(context.Background(), &ToolRequest{Tool: "test", Args: "payload"})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	require.NotNil(t, resp)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+
+	var requestID string
+
+	for i, line := range lines {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+
+		assert.Equal(t, "test", entry["tool"])
+
+		id, ok := entry["request_id"].(string)
+		require.True(t, ok, "log line %d has no request_id", i)
+		assert.Len(t, id, 16)
+
+		if i == 0 {
+			requestID = id
+		}
+
+		assert.Equal(t, requestID, id)
+	}
+
+	assert.Contains(t, lines[0], "tool request started")
+	assert.Contains(t, lines[1], "inside handler")
+	assert.Contains(t, lines[2], "tool request finished")
+	assert.Contains(t, lines[2], `"duration"`)
+	assert.Contains(t, lines[2], `"success":false`)
+}
+
+func TestWrapTool_MiddlewareOrder(t *testing.T) {
+	var calls []string
+
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *ToolRequest) (*mcp.ToolResponse, error) {
+				calls = append(calls, name+":"+req.Tool)
+				return next(ctx, req)
+			}
+		}
+	}
+
+	handler := wrapTool("test", func(_ context.Context, args string) (*mcp.ToolResponse, error) {
+		calls = append(calls, "handler:"+args)
+		return mcp.NewToolResponse(mcp.NewTextContent(args)), nil
+	}, []Middleware{record("first"), record("second")})
+
+	resp, err := handler(context.Background(), "payload")
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, []string{"first:test", "second:test", "handler:payload"}, calls)
+}
+
+func TestWrapTool_TransformArgs(t *testing.T) {
+	upper := func(next Handler) Handler {
+		return func(ctx context.Context, req *ToolRequest) (*mcp.ToolResponse, error) {
+			if args, ok := req.Args.(string); ok {
+				req.Args = strings.ToUpper(args)
+			}
+
+			return next(ctx, req)
+		}
+	}
+
+	handler := wrapTool("test", func(_ context.Context, args string) (*mcp.ToolResponse, error) {
+		return mcp.NewToolResponse(mcp.NewTextContent(args)), nil
+	}, []Middleware{upper})
+
+	resp, err := handler(context.Background(), "payload")
+
+	require.NoError(t, err)
+	assert.Equal(t, "PAYLOAD", resp.Content[0].TextContent.Text)
+}
+
+func TestWrapTool_UnexpectedArgs(t *testing.T) {
+	replace := func(next Handler) Handler {
+		return func(ctx context.Context, req *ToolRequest) (*mcp.ToolResponse, error) {
+			req.Args = 42
+			return next(ctx, req)
+		}
+	}
+
+	handler := wrapTool("test", func(_ context.Context, args string) (*mcp.ToolResponse, error) {
+		return mcp.NewToolResponse(mcp.NewTextContent(args)), nil
+	}, []Middleware{replace})
+
+	resp, err := handler(context.Background(), "payload")
+
+	assert.ErrorIs(t, err, ErrUnexpectedArgs)
+	assert.Nil(t, resp)
+}
+
+func TestWrapTool_ShortCircuit(t *testing.T) {
+	deny := func(_ Handler) Handler {
+		return func(_ context.Context, _ *ToolRequest) (*mcp.ToolResponse, error) {
+			return nil, assert.AnError
+		}
+	}
+
+	handler := wrapTool("test", func(_ context.Context, _ string) (*mcp.ToolResponse, error) {
+		t.Fatal("handler must not be called")
+		return nil, nil
+	}, []Middleware{deny})
+
+	_, err := handler(context.Background(), "payload")
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	assert.Equal(t, slog.Default(), loggerFromContext(context.Background()))
+
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	ctx := context.WithValue(context.Background(), loggerKey{}, logger)
+
+	assert.Equal(t, logger, loggerFromContext(ctx))
+}
+
+func TestNewRequestID(t *testing.T) {
+	first, second := newRequestID(), newRequestID()
+
+	assert.Len(t, first, 16)
+	assert.NotEqual(t, first, second)
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	const limit = 3
+
+	entered := make(chan struct{}, limit)
+	release := make(chan struct{})
+
+	handler := ConcurrencyLimit(limit)(func(_ context.Context, _ *ToolRequest) (*mcp.ToolResponse, error) {
+		entered <- struct{}{}
+		<-release
+
+		return mcp.NewToolResponse(), nil
+	})
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, limit)
+
+	for range limit {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := handler(context.Background(), &ToolRequest{Tool: "codestyle"})
+			errs <- err
+		}()
+	}
+
+	for range limit {
+		<-entered
+	}
+
+	_, err := handler(context.Background(), &ToolRequest{Tool: "codestyle"})
+	assert.ErrorIs(t, err, ErrBusy)
+
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	_, err = handler(context.Background(), &ToolRequest{Tool: "codestyle"})
+	assert.NoError(t, err, "slots must be released when calls finish")
+}
+
+func TestConcurrencyLimit_SharedAcrossTools(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	limit := []Middleware{ConcurrencyLimit(1)}
+
+	slow := wrapTool("codestyle", func(_ context.Context, _ CodeStyleArgs) (*mcp.ToolResponse, error) {
+		close(entered)
+		<-release
+
+		return mcp.NewToolResponse(), nil
+	}, limit)
+
+	fast := wrapTool("getrules", func(_ context.Context, _ GetRulesArgs) (*mcp.ToolResponse, error) {
+		return mcp.NewToolResponse(), nil
+	}, limit)
+
+	done := make(chan error)
+
+	go func() {
+		_, err := slow(context.Background(), CodeStyleArgs{})
+		done <- err
+	}()
+
+	<-entered
+
+	_, err := fast(context.Background(), GetRulesArgs{})
+	assert.ErrorIs(t, err, ErrBusy)
+
+	close(release)
+	require.NoError(t, <-done)
+
+	_, err = fast(context.Background(), GetRulesArgs{})
+	assert.NoError(t, err)
+}
+
+func TestConcurrencyLimit_Unlimited(t *testing.T) {
+	const calls = 10
+
+	var wg sync.WaitGroup
+
+	release := make(chan struct{})
+	handler := ConcurrencyLimit(0)(func(_ context.Context, _ *ToolRequest) (*mcp.ToolResponse, error) {
+		wg.Done()
+		<-release
+
+		return mcp.NewToolResponse(), nil
+	})
+
+	wg.Add(calls)
+
+	errs := make(chan error, calls)
+
+	for range calls {
+		go func() {
+			_, err := handler(context.Background(), &ToolRequest{})
+			errs <- err
+		}()
+	}
+
+	wg.Wait() // every call is running at once
+	close(release)
+
+	for range calls {
+		assert.NoError(t, <-errs)
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// ErrInvalidPrompt is returned when a configured prompt cannot be registered.
+var ErrInvalidPrompt = errors.New("invalid prompt")
+
+// Prompt is an MCP prompt, a curated entry point that asks the model to apply the rules
+// of some categories to a task, see Config.Prompts.
+type Prompt struct {
+	// Name identifies the prompt, e.g. "review_function"
+	Name string `mapstructure:"name"`
+	// Description is shown to users browsing the prompts of a client
+	Description string `mapstructure:"description"`
+	// Instruction describes the task, e.g. "Review the following Go function against our coding rules."
+	Instruction string `mapstructure:"instruction"`
+	// Categories are the codestyle tool categories the model is asked to fetch, with optional weights
+	Categories string `mapstructure:"categories"`
+}
+
+// DefaultPrompts are registered when Config.Prompts is not set.
+var DefaultPrompts = []Prompt{
+	{
+		Name:        "review_function",
+		Description: "Review a Go function against the coding rules",
+		Instruction: "Review the following Go function against our coding rules. List every rule it violates with a suggested fix.",
+		Categories:  "code:2,documentation",
+	},
+	{
+		Name:        "error_handling",
+		Description: "Generate idiomatic error handling for Go code",
+		Instruction: "Add idiomatic error handling to the following Go code: check every error, wrap it with context and return it to the caller.",
+		Categories:  "code",
+	},
+	{
+		Name:        "write_tests",
+		Description: "Write table-driven tests for Go code",
+		Instruction: "Write tests for the following Go code following our testing conventions.",
+		Categories:  "testing",
+	},
+}
+
+// PromptArgs holds the arguments of a prompt. Prompt arguments can only be strings.
+type PromptArgs struct {
+	// Code is the code the task is about
+	Code *string `json:"code" jsonschema:"description=Optional Go code the task is about"`
+}
+
+// setupPrompts registers the configured prompts, or DefaultPrompts if none are configured.
+// Prompts naming categories outside the allowed categories are not registered.
+// Returns error wrapping ErrInvalidPrompt if a prompt has no name or invalid categories,
+// or if registration or the repository stats fail.
+func (s *Service) setupPrompts(ctx context.Context, server *mcp.Server) error {
+	prompts := s.config.Prompts
+	if prompts == nil {
+		prompts = DefaultPrompts
+	}
+
+	for _, prompt := range prompts {
+		if prompt.Name == "" {
+			return fmt.Errorf("%w: name is required", ErrInvalidPrompt)
+		}
+
+		requested, _, _ := parseCategories(prompt.Categories) // checked by Validate
+
+		known, err := s.knownCategories(ctx, requested)
+		if err != nil {
+			return err
+		}
+
+		args := CodeStyleArgs{Categories: prompt.Categories}
+		if err := args.Validate(known); err != nil {
+			return fmt.Errorf("%w %s: %w", ErrInvalidPrompt, prompt.Name, err)
+		}
+
+		if _, err := s.restrictCategories(requested); errors.Is(err, ErrCategoryNotAllowed) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if err := server.RegisterPrompt(prompt.Name, prompt.Description, promptHandler(prompt)); err != nil {
+			return fmt.Errorf("register %s prompt: %w", prompt.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// promptHandler returns the handler of prompt, asking the model to fetch the rules of
+// the prompt categories with the codestyle tool before carrying out the instruction.
+func promptHandler(prompt Prompt) func(args PromptArgs) (*mcp.PromptResponse, error) {
+	return func(args PromptArgs) (*mcp.PromptResponse, error) {
+		var sb strings.Builder
+
+		fmt.Fprintf(&sb, "First call the codestyle tool with categories %q and apply the returned rules.", prompt.Categories)
+
+		if prompt.Instruction != "" {
+			sb.WriteString("\n\n" + prompt.Instruction)
+		}
+
+		if args.Code != nil && *args.Code != "" {
+			fmt.Fprintf(&sb, "\n\n```go\n%s\n```", strings.TrimRight(*args.Code, "\n"))
+		}
+
+		return mcp.NewPromptResponse(prompt.Description, mcp.NewPromptMessage(mcp.NewTextContent(sb.String()), mcp.RoleUser)), nil
+	}
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	mcp "github.com/metoro-io/mcp-golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// servePrompts starts a server with the prompts of svc and returns a function
+// sending a request to it and returning the raw response line.
+func servePrompts(t *testing.T, svc *Service) func(request string) string {
+	t.Helper()
+
+	return serve(t, svc, func(server *mcp.Server) error {
+		return svc.setupPrompts(context.Background(), server)
+	})
+}
+
+func TestService_setupPrompts(t *testing.T) {
+	tests := []struct {
+		name    string
+		prompts []Prompt
+		allowed []string
+		want    []string
+	}{
+		{
+			name: "default prompts",
+			want: []string{"error_handling", "review_function", "write_tests"},
+		},
+		{
+			name:    "configured prompts",
+			prompts: []Prompt{{Name: "concurrency_review", Categories: "code/concurrency"}},
+			want:    []string{"concurrency_review"},
+		},
+		{
+			name:    "prompts with repository categories",
+			prompts: []Prompt{{Name: "security_review", Categories: "security:2,code"}},
+			want:    []string{"security_review"},
+		},
+		{
+			name:    "no prompts",
+			prompts: []Prompt{},
+			want:    []string{},
+		},
+		{
+			name:    "prompts outside allowed categories are skipped",
+			allowed: []string{"testing"},
+			want:    []string{"write_tests"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().Stats(mock.Anything).Return(core.RepoStats{RulesPerCategory: map[string]int{"security": 1}}, nil).Maybe()
+
+			svc := New(&Config{Prompts: tt.prompts, AllowedCategories: tt.allowed}, handler, ServerInfo{})
+			send := servePrompts(t, svc)
+
+			var resp struct {
+				Result struct {
+					Prompts []struct {
+						Name      string `json:"name"`
+						Arguments []struct {
+							Name string `json:"name"`
+						} `json:"arguments"`
+					} `json:"prompts"`
+				} `json:"result"`
+			}
+
+			require.NoError(t, json.Unmarshal([]byte(send(`{"jsonrpc":"2.0","id":1,"method":"prompts/list","params":{}}`)), &resp))
+
+			names := make([]string, 0, len(resp.Result.Prompts))
+			for _, prompt := range resp.Result.Prompts {
+				names = append(names, prompt.Name)
+
+				require.Len(t, prompt.Arguments, 1)
+				assert.Equal(t, "Code", prompt.Arguments[0].Name)
+			}
+
+			assert.ElementsMatch(t, tt.want, names)
+		})
+	}
+}
+
+func TestService_setupPrompts_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt Prompt
+	}{
+		{name: "missing name", prompt: Prompt{Categories: "code"}},
+		{name: "missing categories", prompt: Prompt{Name: "review"}},
+		{name: "unknown category", prompt: Prompt{Name: "review", Categories: "style"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().Stats(mock.Anything).Return(core.RepoStats{}, nil).Maybe()
+
+			svc := New(&Config{Prompts: []Prompt{tt.prompt}}, handler, ServerInfo{})
+
+			err := svc.setupPrompts(context.Background(), svc.newServer(newStdioTransport(nil, io.Discard, 0)))
+
+			assert.ErrorIs(t, err, ErrInvalidPrompt)
+		})
+	}
+}
+
+func TestPromptHandler(t *testing.T) {
+	prompt := Prompt{
+		Name:        "review_function",
+		Description: "Review a function",
+		Instruction: "Review the following Go function.",
+		Categories:  "code:2,documentation",
+	}
+
+	code := "func f() {}\n"
+
+	tests := []struct {
+		args PromptArgs
+		name string
+		want string
+	}{
+		{
+			name: "with code",
+			args: PromptArgs{Code: &code},
+			want: "First call the codestyle tool with categories \"code:2,documentation\" and apply the returned rules.\n\n" +
+				"Review the following Go function.\n\n```go\nfunc f() {}\n```",
+		},
+		{
+			name: "without code",
+			want: "First call the codestyle tool with categories \"code:2,documentation\" and apply the returned rules.\n\n" +
+				"Review the following Go function.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := promptHandler(prompt)(tt.args)
+
+			require.NoError(t, err)
+			require.Len(t, resp.Messages, 1)
+			assert.Equal(t, "Review a function", *resp.Description)
+			assert.Equal(t, tt.want, resp.Messages[0].Content.TextContent.Text)
+		})
+	}
+}
+
+func TestService_getPrompt(t *testing.T) {
+	svc := New(&Config{}, NewMockToolHandler(t), ServerInfo{})
+	send := servePrompts(t, svc)
+
+	var resp struct {
+		Result struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"messages"`
+		} `json:"result"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(send(
+		`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"write_tests","arguments":{"code":"func Sum(a, b int) int"}}}`,
+	)), &resp))
+
+	require.Len(t, resp.Result.Messages, 1)
+	assert.Equal(t, "user", resp.Result.Messages[0].Role)
+	assert.Contains(t, resp.Result.Messages[0].Content.Text, `codestyle tool with categories "testing"`)
+	assert.Contains(t, resp.Result.Messages[0].Content.Text, "```go\nfunc Sum(a, b int) int\n```")
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// resourceMimeType is the MIME type of rule resources, which are rendered as markdown.
+const resourceMimeType = "text/markdown"
+
+// categoryResourceURI returns the URI of the resource listing the rules of category,
+// e.g. "codestyle://rules/testing".
+func categoryResourceURI(category string) string {
+	return "codestyle://rules/" + category
+}
+
+// setupResources registers a resource for each top-level category, so that clients can
+// browse the rules without calling a tool, see resourceCategories. Categories outside the
+// allowed categories get no resource. The rules are fetched when the resource is read, so
+// reloaded rules are served without registering resources again.
+// Returns error if registration fails.
+func (s *Service) setupResources(server *mcp.Server) error {
+	for _, category := range s.resourceCategories() {
+		categories, err := s.restrictCategories([]string{category})
+		if errors.Is(err, ErrCategoryNotAllowed) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		name := category + " rules"
+		if declared, ok := s.declaredCategory(category); ok && declared.DisplayName != "" {
+			name = declared.DisplayName
+		}
+
+		uri := categoryResourceURI(category)
+		description := fmt.Sprintf("Go coding style guidelines from the %q category: %s", category, s.categorySummary(category))
+
+		if err := server.RegisterResource(uri, name, description, resourceMimeType, s.categoryResource(uri, categories)); err != nil {
+			return fmt.Errorf("register %s resource: %w", uri, err)
+		}
+	}
+
+	return nil
+}
+
+// resourceCategories returns the sorted top-level categories exposed as resources: those of
+// the declared categories when categories are declared, as rules must use them then, and
+// the built-in categories otherwise.
+func (s *Service) resourceCategories() []string {
+	if len(s.config.Categories) == 0 {
+		return slices.DeleteFunc(slices.Sorted(maps.Keys(builtinCategories)), func(category string) bool {
+			return category == core.AllCategories
+		})
+	}
+
+	categories := make([]string, 0, len(s.config.Categories))
+	for _, category := range s.config.Categories {
+		categories = append(categories, categoryRoot(category.Name))
+	}
+
+	slices.Sort(categories)
+
+	return slices.Compact(categories)
+}
+
+// categoryResource returns the handler of the resource at uri, serving the rules of categories
+// with the server defaults of the codestyle tool.
+func (s *Service) categoryResource(uri string, categories []string) func(ctx context.Context) (*mcp.ResourceResponse, error) {
+	return func(ctx context.Context) (*mcp.ResourceResponse, error) {
+		logger := loggerFromContext(ctx)
+		logger.Debug("handling resource read", "uri", uri)
+
+		filter := core.Filter{MaxExamplesPerRule: s.config.MaxExamplesPerRule}
+
+		rules, err := s.handler.GetCodeStyle(ctx, categories, filter)
+		if err != nil {
+			logger.Debug("resource read failed", "uri", uri, "error", err)
+			return nil, fmt.Errorf("get rules by category: %w", err)
+		}
+
+		content, included, err := s.formatResponse(rules, FormatMarkdown, renderOptions{includeExamples: s.includeExamples(CodeStyleArgs{})})
+		if err != nil {
+			return nil, err
+		}
+
+		s.handler.RecordCodeStyle(ctx, categories, filter, rules[:included])
+
+		return mcp.NewResourceResponse(mcp.NewTextEmbeddedResource(uri, content, resourceMimeType)), nil
+	}
+}
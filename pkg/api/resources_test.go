@@ -0,0 +1,154 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	mcp "github.com/metoro-io/mcp-golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// serveResources starts a server with the resources of svc and returns a function
+// sending a request to it and returning the raw response line.
+func serveResources(t *testing.T, svc *Service) func(request string) string {
+	t.Helper()
+
+	return serve(t, svc, svc.setupResources)
+}
+
+// serve starts a server of svc set up by setup and returns a function sending
+// a request to it and returning the raw response line.
+func serve(t *testing.T, svc *Service, setup func(server *mcp.Server) error) func(request string) string {
+	t.Helper()
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+
+	t.Cleanup(func() {
+		_ = inWriter.Close()
+		_ = outReader.Close()
+	})
+
+	server := svc.newServer(newStdioTransport(inReader, outWriter, 0))
+	require.NoError(t, setup(server))
+	require.NoError(t, server.Serve())
+
+	out := bufio.NewReader(outReader)
+
+	return func(request string) string {
+		_, err := inWriter.Write([]byte(request + "\n"))
+		require.NoError(t, err)
+
+		line, err := out.ReadString('\n')
+		require.NoError(t, err)
+
+		return line
+	}
+}
+
+func TestService_setupResources(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		want    []string
+	}{
+		{
+			name: "all categories",
+			want: []string{"codestyle://rules/code", "codestyle://rules/documentation", "codestyle://rules/template", "codestyle://rules/testing"},
+		},
+		{
+			name:    "allowed categories",
+			allowed: []string{"testing", "code/concurrency"},
+			want:    []string{"codestyle://rules/code", "codestyle://rules/testing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(&Config{AllowedCategories: tt.allowed}, NewMockToolHandler(t), ServerInfo{})
+			send := serveResources(t, svc)
+
+			var resp struct {
+				Result struct {
+					Resources []struct {
+						URI      string `json:"uri"`
+						MimeType string `json:"mimeType"`
+					} `json:"resources"`
+				} `json:"result"`
+			}
+
+			require.NoError(t, json.Unmarshal([]byte(send(`{"jsonrpc":"2.0","id":1,"method":"resources/list","params":{}}`)), &resp))
+
+			uris := make([]string, 0, len(resp.Result.Resources))
+			for _, resource := range resp.Result.Resources {
+				uris = append(uris, resource.URI)
+				assert.Equal(t, resourceMimeType, resource.MimeType)
+			}
+
+			assert.Equal(t, tt.want, uris)
+		})
+	}
+}
+
+func TestService_setupResources_DeclaredCategories(t *testing.T) {
+	categories := []Category{
+		{Name: "security", DisplayName: "Security", Description: "Secure coding practices"},
+		{Name: "security/crypto"},
+		{Name: "code"},
+	}
+
+	svc := New(&Config{Categories: categories}, NewMockToolHandler(t), ServerInfo{})
+	send := serveResources(t, svc)
+
+	var resp struct {
+		Result struct {
+			Resources []struct {
+				URI         string `json:"uri"`
+				Name        string `json:"name"`
+				Description string `json:"description"`
+			} `json:"resources"`
+		} `json:"result"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(send(`{"jsonrpc":"2.0","id":1,"method":"resources/list","params":{}}`)), &resp))
+	require.Len(t, resp.Result.Resources, 2)
+
+	assert.Equal(t, "codestyle://rules/code", resp.Result.Resources[0].URI)
+	assert.Equal(t, "code rules", resp.Result.Resources[0].Name)
+	assert.Contains(t, resp.Result.Resources[0].Description, categorySummaries["code"])
+
+	assert.Equal(t, "codestyle://rules/security", resp.Result.Resources[1].URI)
+	assert.Equal(t, "Security", resp.Result.Resources[1].Name)
+	assert.Contains(t, resp.Result.Resources[1].Description, "Secure coding practices")
+}
+
+func TestService_categoryResource(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code/concurrency"}, core.Filter{MaxExamplesPerRule: 2}).
+		Return([]core.Rule{{Name: "rule1", Category: "code/concurrency", Description: "First rule"}}, nil)
+
+	svc := New(&Config{AllowedCategories: []string{"code/concurrency"}, MaxExamplesPerRule: 2}, handler, ServerInfo{})
+	send := serveResources(t, svc)
+
+	var resp struct {
+		Result struct {
+			Contents []struct {
+				URI  string `json:"uri"`
+				Text string `json:"text"`
+			} `json:"contents"`
+		} `json:"result"`
+	}
+
+	line := send(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"codestyle://rules/code"}}`)
+	require.NoError(t, json.Unmarshal([]byte(line), &resp))
+
+	require.Len(t, resp.Result.Contents, 1)
+	assert.Equal(t, "codestyle://rules/code", resp.Result.Contents[0].URI)
+	assert.Equal(t, "## rule1\n\nFirst rule\n", resp.Result.Contents[0].Text)
+}
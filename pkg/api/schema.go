@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/invopop/jsonschema"
+)
+
+// ErrSchemaMismatch is wrapped by errors reported for arguments that do not match the tool input schema.
+var ErrSchemaMismatch = errors.New("does not match schema")
+
+// argsReflector generates tool input schemas with the settings the MCP library
+// uses to publish them, so arguments are checked against the advertised schema.
+var argsReflector = jsonschema.Reflector{
+	Anonymous:                  true,
+	AllowAdditionalProperties:  true,
+	RequiredFromJSONSchemaTags: true,
+	DoNotReference:             true,
+	ExpandedStruct:             true,
+}
+
+// argsSchemas caches the generated schema per argument type.
+var argsSchemas sync.Map
+
+// schemaFor returns the input schema of the argument type t.
+func schemaFor(t reflect.Type) *jsonschema.Schema {
+	if schema, ok := argsSchemas.Load(t); ok {
+		return schema.(*jsonschema.Schema)
+	}
+
+	schema, _ := argsSchemas.LoadOrStore(t, argsReflector.ReflectFromType(t))
+
+	return schema.(*jsonschema.Schema)
+}
+
+// unmarshalArgs checks raw tool arguments against the input schema of type t and
+// decodes them into target, which must not implement json.Unmarshaler itself.
+// The type, enum, minimum and maximum keywords are checked for every value present;
+// required properties are left to the Validate methods, so that configured defaults
+// can fill in missing arguments.
+// Returns *ValidationError listing every mismatch with its JSON pointer path.
+func unmarshalArgs(data []byte, t reflect.Type, target any) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	if issues := checkValue(schemaFor(t), value, ""); len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+
+	return json.Unmarshal(data, target)
+}
+
+// checkValue reports every value in the document that does not match the type, enum
+// or range declared by the schema, recursing into object properties and array items.
+func checkValue(schema *jsonschema.Schema, value any, path string) []error {
+	if got := jsonType(value); schema.Type != "" && !typeMatches(schema.Type, value) {
+		return []error{fmt.Errorf("%w: %s: expected %s, got %s", ErrSchemaMismatch, pathOrRoot(path), schema.Type, got)}
+	}
+
+	if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, value) {
+		return []error{fmt.Errorf("%w: %s: expected one of %v, got %v", ErrSchemaMismatch, pathOrRoot(path), schema.Enum, value)}
+	}
+
+	if err := checkRange(schema, value); err != nil {
+		return []error{fmt.Errorf("%w: %s: %w", ErrSchemaMismatch, pathOrRoot(path), err)}
+	}
+
+	var issues []error
+
+	switch v := value.(type) {
+	case map[string]any:
+		if schema.Properties == nil {
+			return nil
+		}
+
+		for pair := schema.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			if prop, ok := v[pair.Key]; ok && prop != nil {
+				issues = append(issues, checkValue(pair.Value, prop, path+"/"+pair.Key)...)
+			}
+		}
+	case []any:
+		if schema.Items == nil {
+			return nil
+		}
+
+		for i, item := range v {
+			issues = append(issues, checkValue(schema.Items, item, fmt.Sprintf("%s/%d", path, i))...)
+		}
+	}
+
+	return issues
+}
+
+// checkRange returns an error if value is a number outside the minimum and maximum of the schema.
+func checkRange(schema *jsonschema.Schema, value any) error {
+	n, ok := value.(float64)
+	if !ok {
+		return nil
+	}
+
+	if minimum, err := schema.Minimum.Float64(); err == nil && n < minimum {
+		return fmt.Errorf("expected at least %v, got %v", minimum, n)
+	}
+
+	if maximum, err := schema.Maximum.Float64(); err == nil && n > maximum {
+		return fmt.Errorf("expected at most %v, got %v", maximum, n)
+	}
+
+	return nil
+}
+
+// typeMatches reports whether value is an instance of the JSON Schema type.
+func typeMatches(schemaType string, value any) bool {
+	got := jsonType(value)
+
+	switch schemaType {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	case "number":
+		return got == "number"
+	default:
+		return got == schemaType
+	}
+}
+
+// jsonType returns the JSON type name of a decoded JSON value.
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// pathOrRoot returns the JSON pointer path, or "/" for the document root.
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	return path
+}
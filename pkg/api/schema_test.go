@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeStyleArgs_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantIssues []string
+		want       CodeStyleArgs
+	}{
+		{
+			name: "valid arguments",
+			data: `{"categories": "code", "per_category_limit": 2, "include_examples": false, "unknown": [1]}`,
+			want: CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{PerCategoryLimit: 2, IncludeExamples: new(bool)}},
+		},
+		{
+			name: "missing categories are left to Validate",
+			data: `{}`,
+			want: CodeStyleArgs{},
+		},
+		{
+			name: "null property is ignored",
+			data: `{"categories": "code", "format": null}`,
+			want: CodeStyleArgs{Categories: "code"},
+		},
+		{
+			name:       "categories sent as array",
+			data:       `{"categories": ["code", "testing"]}`,
+			wantIssues: []string{"/categories: expected string, got array"},
+		},
+		{
+			name: "several type errors",
+			data: `{"categories": "code", "per_category_limit": 1.5, "include_deprecated": "yes"}`,
+			wantIssues: []string{
+				"/per_category_limit: expected integer, got number",
+				"/include_deprecated: expected boolean, got string",
+			},
+		},
+		{
+			name: "enum and range values",
+			data: `{"categories": "code", "min_severity": "must", "sort": "name", "per_category_limit": 0}`,
+			want: CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{MinSeverity: "must", Sort: "name"}},
+		},
+		{
+			name: "enum and range violations",
+			data: `{"categories": "code", "min_severity": "critical", "format": "xml", "per_category_limit": -1, "min_results": -2}`,
+			wantIssues: []string{
+				"/format: expected one of [text markdown], got xml",
+				"/min_severity: expected one of [must should may], got critical",
+				"/per_category_limit: expected at least 0, got -1",
+				"/min_results: expected at least 0, got -2",
+			},
+		},
+		{
+			name:       "arguments are not an object",
+			data:       `"code"`,
+			wantIssues: []string{"/: expected object, got string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args CodeStyleArgs
+
+			err := json.Unmarshal([]byte(tt.data), &args)
+
+			if len(tt.wantIssues) > 0 {
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				require.Len(t, validationErr.Issues, len(tt.wantIssues))
+				assert.ErrorIs(t, err, ErrSchemaMismatch)
+
+				for i, want := range tt.wantIssues {
+					assert.Contains(t, validationErr.Issues[i].Error(), want)
+				}
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, args)
+		})
+	}
+}
+
+func TestArgs_UnmarshalJSONThroughInterface(t *testing.T) {
+	// The MCP library decodes arguments through an interface holding a pointer
+	var getRules any = &GetRulesArgs{}
+
+	err := json.Unmarshal([]byte(`{"names": 42}`), &getRules)
+	assert.ErrorIs(t, err, ErrSchemaMismatch)
+	assert.ErrorContains(t, err, "/names: expected string, got number")
+
+	var stats any = &StatsArgs{}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"client": "team-a"}`), &stats))
+	assert.Equal(t, &StatsArgs{Client: "team-a"}, stats)
+}
+
+func TestUnmarshalArgs_InvalidJSON(t *testing.T) {
+	var args CodeStyleArgs
+
+	err := args.UnmarshalJSON([]byte(`{"categories":`))
+
+	assert.ErrorContains(t, err, "invalid arguments")
+}
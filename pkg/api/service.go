@@ -1,20 +1,27 @@
 // Package api implements the MCP (Model Context Protocol) server functionality.
 //
-// It provides a Service that registers and handles MCP tools for code generation rule management.
+// It provides a Service that registers and handles MCP tools for code generation rule management,
+// and exposes the rules of each category as a read-only MCP resource.
 // The package uses stdio transport for MCP communication and supports concurrent operations
-// through error groups. Each tool is registered with debug logging for request tracking.
+// through error groups. Each tool call passes through a configurable middleware chain,
+// which by default provides debug logging for request tracking.
 package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/ksysoev/mcp-go-tools/pkg/core"
 	mcp "github.com/metoro-io/mcp-golang"
-	"github.com/metoro-io/mcp-golang/transport/stdio"
+	"github.com/metoro-io/mcp-golang/transport"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -30,58 +37,285 @@ Use this tool when you need to:
 5. Format code according to Go standards
 
 Input Parameters:
-- categories: Comma separated list of rule categories to filter by
+- categories: Comma separated list of rule categories to filter by, or "*" for all categories
   * "documentation" - rules for comments, package docs, and godoc
   * "testing" - testing conventions, table tests, benchmarks
   * "code" - code organization, naming, interfaces, error handling, concurrency
   * "template" - template for go application structure
+  * Other categories of the server rules (e.g. "security") are accepted too
+  * Nested categories use "/" (e.g. "code/concurrency"); a category also matches all of its descendants
+  * A category may be weighted as "name:weight" with a positive integer (e.g. "testing:2,code"); rules of higher-weighted categories come first, then in the selected sort order, and unweighted categories weigh 1
+- exclude_categories: Optional comma separated list of categories whose rules are never returned, taking precedence over categories (e.g. "*" excluding "documentation"); a category also excludes all of its descendants
+- format: Optional output format, "text" (default) or "markdown"
+- code_contains: Optional substring to match against example code (ignoring case and accents)
+- language: Optional language of the rules (e.g. "typescript"); when it has no matching rules, the next language of the server fallback chain is tried, down to the rules without a language
+- min_results: Optional minimum number of rules wanted (0 means no minimum)
+- widen_on_insufficient: Optional flag to drop code_contains and query again when fewer than min_results rules match
+- per_category_limit: Optional maximum number of rules per category (0 means unlimited)
+- max_examples_per_rule: Optional maximum number of examples per rule, preferring examples that match code_contains (0 means the server default)
+- min_severity: Optional minimum rule severity, one of "must", "should", "may"
+- include_deprecated: Optional flag to include deprecated rules, which are excluded by default
+- include_examples: Optional flag to include or omit code examples, overriding the server default
+- include_category: Optional flag to precede each rule with a "Category:" line in text output, on by default when several categories or "*" are requested
+- names_only: Optional flag to return only the names and categories of the matching rules, one "name (category)" per line, as a cheap overview before requesting specific rules with getrules
+- with_examples_only: Optional flag to return only rules that have code examples
+- updated_since: Optional RFC3339 time, only rules updated at or after it are returned
+- sort: Optional order of rules:
+  * "canonical" (default) - by category and name
+  * "updated" - most recently updated first, rules without an update time last
+  * "severity" - MUST rules first, then SHOULD, then MAY
+  * "name" - by rule name across categories
+  * "manual" - the curated order set by the server, ties in configuration order
+  * "popularity" - most frequently returned first, ties by category and name; counts reset when the rules are reloaded
+- client: Optional client identifier, used to serve a client-specific subset of rules
+- model: Optional name of the client model; the response is capped to a share of its context window, omitting trailing rules with a truncation notice
 
 Returns:
 - Array of matching style rules, each containing:
+  * Severity ([MUST], [SHOULD] or [MAY], as defined by RFC 2119)
   * Name and description
+  * Time of the last update, if known
   * Code templates and examples
+- A second content block with a JSON object describing the response:
+  * matched: Number of returned rules
+  * categories: Categories queried
+  * truncated: Whether per_category_limit or the response size cap left out matching rules
+  * broadened: Whether code_contains was dropped because fewer than min_results rules matched, omitted otherwise
+  * insufficient: Whether fewer than min_results rules are returned, omitted otherwise
+  * language: Language that supplied the rules when a language was requested, empty for rules without a language, omitted otherwise
+  * conflicts: Pairs of returned rules that give contradictory advice, omitted when there are none; weigh them against each other before applying either
+`
+
+const statsDescription = `Retrieve aggregate information about the available coding style rules.
+
+Use this tool to get a quick overview before requesting rules from specific categories.
+
+Input Parameters:
+- client: Optional client identifier, used to serve a client-specific subset of rules
+
+Returns a JSON object with:
+- total_rules: Total number of rules
+- rules_per_category: Number of rules in each category
+- rules_with_examples: Number of rules that include code examples
+
+When the server restricts the categories clients can query, only the rules of the allowed categories are counted.
+`
+
+const listCategoriesDescription = `List the categories of the available coding style rules.
+
+Use this tool to discover which categories to request from the codestyle tool and what each of them covers.
+
+Input Parameters:
+- client: Optional client identifier, used to serve a client-specific subset of rules
+
+Returns a JSON array sorted by name, with for each category:
+- name: Category name, nested categories use "/" (e.g. "code/concurrency")
+- display_name: Human-readable name, if declared by the server
+- description: Summary of the rules in the category
+- rules: Number of rules in the category and its nested categories
+
+The categories are those declared by the server and those used by the rules. When the server restricts the categories clients can query, only the allowed categories are listed.
+`
+
+const fingerprintDescription = `Retrieve a fingerprint of the available coding style rules.
+
+Use this tool to check whether two servers serve identical rules: their fingerprints are equal exactly when the rules are. The fingerprint does not depend on the order of the rules.
+
+Input Parameters:
+- client: Optional client identifier, used to serve a client-specific subset of rules
+
+Returns a JSON object with:
+- fingerprint: Hex encoded SHA-256 hash of the rules
+`
+
+const ruleHitsDescription = `Retrieve how many times each coding style rule was returned.
+
+Use this tool to find the rules agents actually use, and the ones never returned that are candidates for pruning.
+
+Returns a JSON object mapping rule names to the number of times codestyle, getrules, recommend or rendertemplate returned them since the rules were last loaded. Rules that were not returned are absent.
+`
+
+const getRulesDescription = `Retrieve specific coding style rules by their names.
+
+Use this tool to re-fetch rules previously returned by the codestyle tool without repeating the category query.
+
+Input Parameters:
+- names: Comma separated list of rule names
+- format: Optional output format, "text" (default) or "markdown"
+- client: Optional client identifier, used to serve a client-specific subset of rules
+
+Returns:
+- The matching rules, in the requested order
+- When some names match no rule, a separate content item listing them, prefixed with "Missing rules:"
+`
+
+const recommendDescription = `Recommend coding style rules relevant to a code snippet.
+
+Use this tool to find the guidelines that apply to code you are reviewing or about to change, without knowing the rule categories or names.
+
+Input Parameters:
+- code: The code snippet to find relevant rules for
+- limit: Optional maximum number of rules to return (default 5)
+- format: Optional output format, "text" (default) or "markdown"
+- client: Optional client identifier, used to serve a client-specific subset of rules
+
+Returns:
+- The most relevant rules first, ranked by the number of identifiers they share with the snippet
+- Rules sharing no identifier with the snippet are not returned
+`
+
+const renderTemplateDescription = `Render the code template of a coding style rule.
+
+Use this tool to generate scaffolding from a rule whose description includes a template, instead of writing the code from scratch.
+
+Input Parameters:
+- rule: Name of the rule whose template to render
+- variables: Optional object mapping template variable names to values, referenced in the template as {{.name}}
+- client: Optional client identifier, used to serve a client-specific subset of rules
+
+Returns:
+- The rendered template
+- An error naming the problem if the rule is unknown, has no template, or a variable is missing
 `
 
 // ToolHandler defines the interface for handling code generation rule operations.
 // Implementations must be safe for concurrent use as methods may be called
 // simultaneously by different MCP tool handlers.
 type ToolHandler interface {
-	GetCodeStyle(ctx context.Context, categories []string) ([]core.Rule, error)
+	GetCodeStyle(ctx context.Context, categories []string, filter core.Filter) ([]core.Rule, error)
+	RecordCodeStyle(ctx context.Context, categories []string, filter core.Filter, rules []core.Rule)
+	GetByNames(ctx context.Context, names []string) (rules []core.Rule, missing []string, err error)
+	RecordByNames(ctx context.Context, names []string, rules []core.Rule)
+	Stats(ctx context.Context) (core.RepoStats, error)
+	Fingerprint(ctx context.Context) (string, error)
+	Recommend(ctx context.Context, code string, categories []string, limit int) ([]core.Rule, error)
+	RuleHits() map[string]int
 }
 
+// Supported output formats for the codestyle tool.
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+)
+
 // Config holds the service configuration parameters.
-// Currently empty but maintained for future configuration options.
 type Config struct {
+	// DefaultCategories are used when the codestyle tool is called without categories.
+	// Use "*" to return rules from all categories. If empty, categories are required.
+	DefaultCategories []string `mapstructure:"defaultCategories"`
+	// AuditLog is the path of a file the rule queries are appended to, one JSON line each,
+	// apart from the diagnostic logs. If empty, queries are not audited.
+	AuditLog string `mapstructure:"auditLog"`
+	// ResponsePreamble is an instruction prepended to the rules of codestyle, getrules and
+	// recommend responses, e.g. on how to resolve conflicting rules. It is not counted towards
+	// MaxResponseBytes. If empty, responses start with the rules.
+	ResponsePreamble string `mapstructure:"responsePreamble"`
+	// LanguageFallback maps a language to the next one tried when a codestyle request for it
+	// matches no rules, e.g. "typescript" to "javascript" and "javascript" to "" for the rules
+	// without a language. See core.Service.SetLanguageFallback.
+	LanguageFallback map[string]string `mapstructure:"languageFallback"`
+	// IncludeExamples controls whether rule examples are included in responses.
+	// Defaults to true when not set; can be overridden per request.
+	IncludeExamples *bool `mapstructure:"includeExamples"`
+	// Prompts are registered as MCP prompts pre-filling the codestyle tool usage for common
+	// workflows. If nil, DefaultPrompts are registered; an empty list registers none.
+	Prompts []Prompt `mapstructure:"prompts"`
+	// Categories are the declared rule categories. Their descriptions are used in the per-category
+	// tool and resource descriptions, and they are accepted by the codestyle tool even without
+	// rules. They are set from the top-level categories config section rather than the api section.
+	Categories []Category `mapstructure:"-"`
+	// AllowedCategories restricts the categories clients can query. The "*" wildcard
+	// expands to these categories, and rules from other categories are never returned.
+	// If empty, all categories are allowed.
+	AllowedCategories []string `mapstructure:"allowedCategories"`
+	// CategoryTools lists categories served by a dedicated tool each, named after the
+	// category, e.g. "get_testing_rules" for "testing". The codestyle tool remains available.
+	CategoryTools []string `mapstructure:"categoryTools"`
+	// MaxResponseBytes caps the size of formatted rules in a response. Rules that do not
+	// fit are omitted with a truncation notice. If zero, responses are not capped.
+	MaxResponseBytes int `mapstructure:"maxResponseBytes"`
+	// MaxExamplesPerRule caps the number of examples of each rule in codestyle responses
+	// when the request does not set max_examples_per_rule. If zero, examples are not capped.
+	MaxExamplesPerRule int `mapstructure:"maxExamplesPerRule"`
+	// DefaultTokenBudget caps codestyle responses, in tokens, for clients passing a model
+	// the server does not know. If zero, such responses are only capped by MaxResponseBytes.
+	DefaultTokenBudget int `mapstructure:"defaultTokenBudget"`
+	// MaxRequestBytes caps the size of a single request line read from stdin.
+	// If zero, DefaultMaxRequestBytes is used.
+	MaxRequestBytes int `mapstructure:"maxRequestBytes"`
+	// MaxConcurrentRequests caps the number of tool calls handled at once; calls beyond
+	// it fail with ErrBusy, see ConcurrencyLimit. If zero, concurrency is not limited.
+	MaxConcurrentRequests int `mapstructure:"maxConcurrentRequests"`
+	// StrictCategories makes the codestyle tool reject requested categories without any rule,
+	// so that typos are noticed. By default such categories just contribute no rules.
+	StrictCategories bool `mapstructure:"strictCategories"`
+	// PrefixCategories lets a requested category that names no known category select every
+	// category starting with it, e.g. "test" selects "testing". It is set from the
+	// repository.prefixCategories config key rather than the api section.
+	PrefixCategories bool `mapstructure:"-"`
+	// PrettyJSON indents JSON responses, such as stats and the codestyle metadata,
+	// for human readers. Defaults to compact JSON to save tokens.
+	PrettyJSON bool `mapstructure:"prettyJSON"`
+}
+
+// ServerInfo identifies the server to MCP clients during the initialize handshake.
+type ServerInfo struct {
+	Name    string
+	Version string
 }
 
 // Service implements the MCP server functionality for code generation rules.
 // It registers tools for rule management and handles their execution through
 // the provided ToolHandler. The service is safe for concurrent use.
 type Service struct {
-	config  *Config
-	handler ToolHandler
+	config      *Config
+	handler     ToolHandler
+	info        ServerInfo
+	middlewares []Middleware
 }
 
 // New creates a new Service instance with the provided configuration and handler.
 // The handler must be properly initialized and safe for concurrent use.
-func New(cfg *Config, handler ToolHandler) *Service {
+// The info is reported to MCP clients as the server identity.
+// Middlewares are applied to every tool call, the first one being the outermost;
+// when none are given, DefaultMiddlewares are used. When cfg.MaxConcurrentRequests is set,
+// a ConcurrencyLimit middleware is applied innermost, so that rejected calls are seen by the others.
+func New(cfg *Config, handler ToolHandler, info ServerInfo, middlewares ...Middleware) *Service {
+	if len(middlewares) == 0 {
+		middlewares = DefaultMiddlewares()
+	}
+
+	if cfg.MaxConcurrentRequests > 0 {
+		middlewares = append(slices.Clip(middlewares), ConcurrencyLimit(cfg.MaxConcurrentRequests))
+	}
+
 	return &Service{
-		config:  cfg,
-		handler: handler,
+		config:      cfg,
+		handler:     handler,
+		info:        info,
+		middlewares: middlewares,
 	}
 }
 
 // Run starts the MCP server and begins handling tool requests.
-// It sets up all available tools and starts the server with stdio transport.
+// It sets up all available tools, resources and prompts and starts the server with stdio transport.
 // The server runs until the context is cancelled or an error occurs.
-// Returns error if tool setup fails or server encounters an error.
+// Returns error if tool, resource or prompt setup fails or server encounters an error.
 func (s *Service) Run(ctx context.Context) error {
-	server := mcp.NewServer(stdio.NewStdioServerTransport())
+	server := s.newServer(newStdioTransport(os.Stdin, os.Stdout, s.config.MaxRequestBytes))
 
-	if err := s.setupTools(server); err != nil {
+	if err := s.setupTools(ctx, server); err != nil {
 		return fmt.Errorf("failed to setup tools: %w", err)
 	}
 
+	if err := s.setupResources(server); err != nil {
+		return fmt.Errorf("failed to setup resources: %w", err)
+	}
+
+	if err := s.setupPrompts(ctx, server); err != nil {
+		return fmt.Errorf("failed to setup prompts: %w", err)
+	}
+
 	eg, ctx := errgroup.WithContext(ctx)
 
 	eg.Go(server.Serve)
@@ -104,55 +338,1022 @@ func (s *Service) Run(ctx context.Context) error {
 	return nil
 }
 
+// newServer creates an MCP server on the given transport, identified by the service info.
+func (s *Service) newServer(t transport.Transport) *mcp.Server {
+	return mcp.NewServer(t,
+		mcp.WithName(s.info.Name),
+		mcp.WithVersion(s.info.Version),
+	)
+}
+
 // Tool argument types define the expected input parameters for each tool.
 // These types are used for JSON unmarshaling of tool arguments.
 
+// FilterArgs holds the rule filter and response parameters shared by the codestyle
+// tool and the per-category tools.
+type FilterArgs struct {
+	// IncludeExamples overrides the configured default for including examples
+	IncludeExamples *bool `json:"include_examples,omitempty" jsonschema:"description=Include code examples in the response. Defaults to the server configuration"`
+	// Client identifies the caller for client-specific rule subsets
+	Client string `json:"client" jsonschema:"description=Optional client identifier used to serve a client-specific subset of rules"`
+	// Format of the response content
+	Format string `json:"format" jsonschema:"enum=text,enum=markdown,description=Output format: 'text' (default) or 'markdown'"`
+	// CodeContains restricts results to rules with matching example code
+	CodeContains string `json:"code_contains" jsonschema:"description=Only return rules with an example whose code contains this substring (ignoring case and accents)"`
+	// MinSeverity restricts results to rules at least as strict as this severity
+	MinSeverity string `json:"min_severity" jsonschema:"enum=must,enum=should,enum=may,description=Only return rules with at least this severity: 'must', 'should' or 'may'"`
+	// Model names the client model, used to size the response to its context window
+	Model string `json:"model" jsonschema:"description=Optional name of the client model (e.g. 'gpt-4o' or 'claude-sonnet-4'). Responses are capped to fit its context window"`
+	// Language restricts results to rules of this language, falling back along the configured chain
+	Language string `json:"language" jsonschema:"description=Optional language of the rules (e.g. 'typescript'). When it has no matching rules the languages of the server fallback chain are tried in turn; the metadata names the language that supplied the rules"`
+	// UpdatedSince restricts results to rules updated at or after this RFC3339 time
+	UpdatedSince string `json:"updated_since" jsonschema:"description=Only return rules updated at or after this RFC3339 time. Rules without an update time are excluded"`
+	// Sort selects the order of returned rules
+	Sort string `json:"sort" jsonschema:"enum=canonical,enum=updated,enum=severity,enum=name,enum=manual,enum=popularity,description=Order of returned rules: 'canonical' (default; by category and name); 'updated' (most recently updated first); 'severity' (strictest first); 'name'; 'manual' (curated server order); or 'popularity' (most frequently returned first)"`
+	// PerCategoryLimit caps the number of rules returned per category
+	PerCategoryLimit int `json:"per_category_limit" jsonschema:"minimum=0,description=Maximum number of rules returned per category. 0 means unlimited"`
+	// MaxExamplesPerRule caps the number of examples of each rule
+	MaxExamplesPerRule int `json:"max_examples_per_rule" jsonschema:"minimum=0,description=Maximum number of examples returned per rule; examples matching code_contains are preferred. 0 means the server default"`
+	// MinResults is the number of rules the caller wants at least, see WidenOnInsufficient
+	MinResults int `json:"min_results" jsonschema:"minimum=0,description=Minimum number of rules wanted. The metadata reports when fewer are returned. 0 means no minimum"`
+	// IncludeDeprecated includes deprecated rules in the response
+	IncludeDeprecated bool `json:"include_deprecated" jsonschema:"description=Include deprecated rules, which are excluded by default"`
+	// WithExamplesOnly restricts results to rules with code examples
+	WithExamplesOnly bool `json:"with_examples_only" jsonschema:"description=Only return rules that have at least one code example"`
+	// WidenOnInsufficient drops the code_contains filter when fewer than MinResults rules match
+	WidenOnInsufficient bool `json:"widen_on_insufficient" jsonschema:"description=Drop code_contains and query again when fewer than min_results rules match; the metadata reports the broadening"`
+	// NamesOnly lists the names and categories of the matching rules without their content
+	NamesOnly bool `json:"names_only" jsonschema:"description=Return only the names and categories of the matching rules as 'name (category)' lines. Use getrules to fetch specific rules afterwards"`
+}
+
+// filter returns the repository filter selected by the arguments.
+// The update time must have been checked by Validate.
+func (a *FilterArgs) filter() core.Filter {
+	filter := core.Filter{
+		CodeContains:       a.CodeContains,
+		MinSeverity:        a.MinSeverity,
+		Language:           a.Language,
+		PerCategoryLimit:   a.PerCategoryLimit,
+		MaxExamplesPerRule: a.MaxExamplesPerRule,
+		IncludeDeprecated:  a.IncludeDeprecated,
+		WithExamplesOnly:   a.WithExamplesOnly,
+		SortBy:             a.Sort,
+	}
+
+	if a.UpdatedSince != "" {
+		filter.UpdatedSince, _ = time.Parse(time.RFC3339, a.UpdatedSince)
+	}
+
+	return filter
+}
+
 // CategoryArgs holds the category parameter for rule filtering.
 // Used to specify the category of code generation rules to retrieve.
 type CodeStyleArgs struct {
+	// IncludeCategory overrides whether text output names the category of each rule
+	IncludeCategory *bool `json:"include_category,omitempty" jsonschema:"description=Precede each rule with a 'Category:' line in text output. Defaults to true when several categories or '*' are requested"`
 	// Categories for filtering rules
-	Categories string `json:"categories" jsonschema:"required,description=The categories for filtering code generation rules. Comma-separated list of: 'documentation', 'testing', 'code'"`
+	Categories string `json:"categories" jsonschema:"required,description=The categories for filtering code generation rules. Comma-separated list of: 'documentation', 'testing', 'code', or '*' for all categories. Nested categories such as 'code/concurrency' are matched by their parents. Append a positive weight such as 'testing:2' to return the rules of higher-weighted categories first; categories weigh 1 by default"`
+	// ExcludeCategories lists categories whose rules are dropped, taking precedence over Categories
+	ExcludeCategories string `json:"exclude_categories" jsonschema:"description=Comma-separated list of categories whose rules are never returned; nested categories are excluded with their parents. Takes precedence over categories (e.g. '*' with 'documentation' returns everything except documentation)"`
+	FilterArgs
+}
+
+// UnmarshalJSON decodes codestyle arguments after checking them against the tool input schema.
+func (a *CodeStyleArgs) UnmarshalJSON(data []byte) error {
+	type plain CodeStyleArgs
+	return unmarshalArgs(data, reflect.TypeFor[CodeStyleArgs](), (*plain)(a))
+}
+
+// GetRulesArgs holds the parameters of the getrules tool.
+type GetRulesArgs struct {
+	// Names of the rules to retrieve
+	Names string `json:"names" jsonschema:"required,description=Comma-separated list of rule names to retrieve"`
+	// Format of the response content
+	Format string `json:"format" jsonschema:"enum=text,enum=markdown,description=Output format: 'text' (default) or 'markdown'"`
+	// Client identifies the caller for client-specific rule subsets
+	Client string `json:"client" jsonschema:"description=Optional client identifier used to serve a client-specific subset of rules"`
+}
+
+// UnmarshalJSON decodes getrules arguments after checking them against the tool input schema.
+func (a *GetRulesArgs) UnmarshalJSON(data []byte) error {
+	type plain GetRulesArgs
+	return unmarshalArgs(data, reflect.TypeFor[GetRulesArgs](), (*plain)(a))
+}
+
+// StatsArgs holds the parameters of the stats tool.
+type StatsArgs struct {
+	// Client identifies the caller for client-specific rule subsets
+	Client string `json:"client" jsonschema:"description=Optional client identifier used to serve a client-specific subset of rules"`
+}
+
+// UnmarshalJSON decodes stats arguments after checking them against the tool input schema.
+func (a *StatsArgs) UnmarshalJSON(data []byte) error {
+	type plain StatsArgs
+	return unmarshalArgs(data, reflect.TypeFor[StatsArgs](), (*plain)(a))
+}
+
+// ListCategoriesArgs holds the parameters of the listcategories tool.
+type ListCategoriesArgs struct {
+	// Client identifies the caller for client-specific rule subsets
+	Client string `json:"client" jsonschema:"description=Optional client identifier used to serve a client-specific subset of rules"`
+}
+
+// UnmarshalJSON decodes listcategories arguments after checking them against the tool input schema.
+func (a *ListCategoriesArgs) UnmarshalJSON(data []byte) error {
+	type plain ListCategoriesArgs
+	return unmarshalArgs(data, reflect.TypeFor[ListCategoriesArgs](), (*plain)(a))
+}
+
+// categoryInfo describes a category in the listcategories response.
+type categoryInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name,omitempty"`
+	Description string `json:"description"`
+	Rules       int    `json:"rules"`
+}
+
+// FingerprintArgs holds the parameters of the fingerprint tool.
+type FingerprintArgs struct {
+	// Client identifies the caller for client-specific rule subsets
+	Client string `json:"client" jsonschema:"description=Optional client identifier used to serve a client-specific subset of rules"`
+}
+
+// UnmarshalJSON decodes fingerprint arguments after checking them against the tool input schema.
+func (a *FingerprintArgs) UnmarshalJSON(data []byte) error {
+	type plain FingerprintArgs
+	return unmarshalArgs(data, reflect.TypeFor[FingerprintArgs](), (*plain)(a))
+}
+
+// RuleHitsArgs holds the parameters of the rulehits tool, which takes none.
+type RuleHitsArgs struct{}
+
+// UnmarshalJSON decodes rulehits arguments after checking them against the tool input schema.
+func (a *RuleHitsArgs) UnmarshalJSON(data []byte) error {
+	type plain RuleHitsArgs
+	return unmarshalArgs(data, reflect.TypeFor[RuleHitsArgs](), (*plain)(a))
+}
+
+// RecommendArgs holds the parameters of the recommend tool.
+type RecommendArgs struct {
+	// Code is the snippet to recommend rules for
+	Code string `json:"code" jsonschema:"required,description=The code snippet to find relevant rules for"`
+	// Format of the response content
+	Format string `json:"format" jsonschema:"enum=text,enum=markdown,description=Output format: 'text' (default) or 'markdown'"`
+	// Client identifies the caller for client-specific rule subsets
+	Client string `json:"client" jsonschema:"description=Optional client identifier used to serve a client-specific subset of rules"`
+	// Limit caps the number of recommended rules
+	Limit int `json:"limit" jsonschema:"minimum=0,description=Maximum number of rules to return. 0 means the default of 5"`
+}
+
+// UnmarshalJSON decodes recommend arguments after checking them against the tool input schema.
+func (a *RecommendArgs) UnmarshalJSON(data []byte) error {
+	type plain RecommendArgs
+	return unmarshalArgs(data, reflect.TypeFor[RecommendArgs](), (*plain)(a))
+}
+
+// RenderTemplateArgs holds the parameters of the rendertemplate tool.
+type RenderTemplateArgs struct {
+	// Variables are the values substituted into the template
+	Variables map[string]string `json:"variables" jsonschema:"description=Template variables by name; referenced in the template as {{.name}}"`
+	// Rule is the name of the rule whose template is rendered
+	Rule string `json:"rule" jsonschema:"required,description=Name of the rule whose template to render"`
+	// Client identifies the caller for client-specific rule subsets
+	Client string `json:"client" jsonschema:"description=Optional client identifier used to serve a client-specific subset of rules"`
+}
+
+// UnmarshalJSON decodes rendertemplate arguments after checking them against the tool input schema.
+func (a *RenderTemplateArgs) UnmarshalJSON(data []byte) error {
+	type plain RenderTemplateArgs
+	return unmarshalArgs(data, reflect.TypeFor[RenderTemplateArgs](), (*plain)(a))
 }
 
 // setupTools registers all available tools with the MCP server.
-// Each tool is registered with debug logging and proper error handling.
+// Each tool handler is wrapped with the service middlewares. Arguments are checked
+// against the tool input schema while being decoded, before the handler is dispatched.
+// Cancelling ctx aborts the checks of the per-category tools, see setupCategoryTools.
 // Returns error if any tool registration fails.
-func (s *Service) setupTools(server *mcp.Server) error {
-	err := server.RegisterTool("codestyle", codeStyleDescription, s.handleCodeStyle)
+func (s *Service) setupTools(ctx context.Context, server *mcp.Server) error {
+	err := server.RegisterTool("codestyle", codeStyleDescription, wrapTool("codestyle", s.handleCodeStyle, s.middlewares))
 	if err != nil {
 		return fmt.Errorf("register get rules by category tool: %w", err)
 	}
 
-	return nil
+	err = server.RegisterTool("getrules", getRulesDescription, wrapTool("getrules", s.handleGetRules, s.middlewares))
+	if err != nil {
+		return fmt.Errorf("register get rules by names tool: %w", err)
+	}
+
+	err = server.RegisterTool("stats", statsDescription, wrapTool("stats", s.handleStats, s.middlewares))
+	if err != nil {
+		return fmt.Errorf("register stats tool: %w", err)
+	}
+
+	err = server.RegisterTool("listcategories", listCategoriesDescription, wrapTool("listcategories", s.handleListCategories, s.middlewares))
+	if err != nil {
+		return fmt.Errorf("register list categories tool: %w", err)
+	}
+
+	err = server.RegisterTool("fingerprint", fingerprintDescription, wrapTool("fingerprint", s.handleFingerprint, s.middlewares))
+	if err != nil {
+		return fmt.Errorf("register fingerprint tool: %w", err)
+	}
+
+	err = server.RegisterTool("rulehits", ruleHitsDescription, wrapTool("rulehits", s.handleRuleHits, s.middlewares))
+	if err != nil {
+		return fmt.Errorf("register rule hits tool: %w", err)
+	}
+
+	err = server.RegisterTool("recommend", recommendDescription, wrapTool("recommend", s.handleRecommend, s.middlewares))
+	if err != nil {
+		return fmt.Errorf("register recommend tool: %w", err)
+	}
+
+	err = server.RegisterTool("rendertemplate", renderTemplateDescription, wrapTool("rendertemplate", s.handleRenderTemplate, s.middlewares))
+	if err != nil {
+		return fmt.Errorf("register render template tool: %w", err)
+	}
+
+	return s.setupCategoryTools(ctx, server)
+}
+
+// handleGetRules processes the getrules tool request.
+// It returns the requested rules in order, followed by a separate content item
+// listing the names that matched no rule, if any.
+func (s *Service) handleGetRules(ctx context.Context, args GetRulesArgs) (*mcp.ToolResponse, error) {
+	logger := loggerFromContext(ctx)
+	logger.Debug("handling getrules request", "names", args.Names, "client", args.Client)
+	ctx = core.WithClientID(ctx, args.Client)
+
+	if err := args.Validate(); err != nil {
+		logger.Debug("getrules arguments are invalid", "error", err)
+		return nil, err
+	}
+
+	names := splitCategories(args.Names)
+
+	rules, missing, err := s.handler.GetByNames(ctx, names)
+	if err != nil {
+		logger.Debug("get rules by names failed", "error", err)
+		return nil, fmt.Errorf("get rules by names: %w", err)
+	}
+
+	if len(s.config.AllowedCategories) > 0 {
+		rules, missing = s.allowedRules(rules, names)
+	}
+
+	logger.Debug("get rules by names completed", "rules_count", len(rules), "missing_count", len(missing))
+
+	content, _, err := s.formatResponse(rules, args.Format, renderOptions{includeExamples: true})
+	if err != nil {
+		return nil, err
+	}
+
+	s.handler.RecordByNames(ctx, names, rules)
+
+	contents := []*mcp.Content{mcp.NewTextContent(content)}
+	if len(missing) > 0 {
+		contents = append(contents, mcp.NewTextContent("Missing rules: "+strings.Join(missing, ", ")))
+	}
+
+	return mcp.NewToolResponse(contents...), nil
+}
+
+// handleRecommend processes the recommend tool request.
+// It returns the rules most relevant to the code snippet, limited to the allowed categories.
+func (s *Service) handleRecommend(ctx context.Context, args RecommendArgs) (*mcp.ToolResponse, error) {
+	logger := loggerFromContext(ctx)
+	logger.Debug("handling recommend request", "code_length", len(args.Code), "limit", args.Limit, "client", args.Client)
+	ctx = core.WithClientID(ctx, args.Client)
+
+	if err := args.Validate(); err != nil {
+		logger.Debug("recommend arguments are invalid", "error", err)
+		return nil, err
+	}
+
+	categories, err := s.restrictCategories([]string{core.AllCategories})
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.handler.Recommend(ctx, args.Code, categories, args.Limit)
+	if err != nil {
+		logger.Debug("recommend failed", "error", err)
+		return nil, fmt.Errorf("recommend rules: %w", err)
+	}
+
+	logger.Debug("recommend completed", "rules_count", len(rules))
+
+	content, _, err := s.formatResponse(rules, args.Format, renderOptions{includeExamples: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(content)), nil
+}
+
+// handleRenderTemplate processes the rendertemplate tool request.
+// It renders the template of the named rule with the provided variables. When
+// several rules share the name, the first one with a template is used, and only that
+// rule is recorded as returned.
+func (s *Service) handleRenderTemplate(ctx context.Context, args RenderTemplateArgs) (*mcp.ToolResponse, error) {
+	logger := loggerFromContext(ctx)
+	logger.Debug("handling rendertemplate request", "rule", args.Rule, "client", args.Client)
+	ctx = core.WithClientID(ctx, args.Client)
+
+	if err := args.Validate(); err != nil {
+		logger.Debug("rendertemplate arguments are invalid", "error", err)
+		return nil, err
+	}
+
+	names := []string{strings.TrimSpace(args.Rule)}
+
+	rules, _, err := s.handler.GetByNames(ctx, names)
+	if err != nil {
+		logger.Debug("get rules by names failed", "error", err)
+		return nil, fmt.Errorf("get rules by names: %w", err)
+	}
+
+	if len(s.config.AllowedCategories) > 0 {
+		rules, _ = s.allowedRules(rules, names)
+	}
+
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, names[0])
+	}
+
+	rule := rules[0]
+	if i := slices.IndexFunc(rules, func(r core.Rule) bool { return r.Template != "" }); i >= 0 {
+		rule = rules[i]
+	}
+
+	rendered, err := rule.RenderTemplate(args.Variables)
+	if err != nil {
+		logger.Debug("render template failed", "error", err)
+		return nil, fmt.Errorf("render template of rule %s: %w", rule.Name, err)
+	}
+
+	s.handler.RecordByNames(ctx, names, []core.Rule{rule})
+
+	return mcp.NewToolResponse(mcp.NewTextContent(rendered)), nil
+}
+
+// handleStats processes the stats tool request.
+// It returns repository metrics encoded as JSON, restricted to the allowed categories.
+func (s *Service) handleStats(ctx context.Context, args StatsArgs) (*mcp.ToolResponse, error) {
+	logger := loggerFromContext(ctx)
+	ctx = core.WithClientID(ctx, args.Client)
+
+	var (
+		stats core.RepoStats
+		err   error
+	)
+
+	if len(s.config.AllowedCategories) > 0 {
+		stats, err = s.allowedStats(ctx)
+	} else {
+		stats, err = s.handler.Stats(ctx)
+	}
+
+	if err != nil {
+		logger.Debug("stats failed", "error", err)
+		return nil, fmt.Errorf("get stats: %w", err)
+	}
+
+	data, err := s.marshalJSON(stats)
+	if err != nil {
+		return nil, fmt.Errorf("marshal stats: %w", err)
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(string(data))), nil
+}
+
+// allowedStats returns the metrics of the rules in the allowed categories, computed from
+// the rules themselves, so that the stats do not disclose the categories outside the allowlist.
+// Returns error if the rules cannot be read.
+func (s *Service) allowedStats(ctx context.Context) (core.RepoStats, error) {
+	rules, err := s.handler.GetCodeStyle(ctx, s.config.AllowedCategories, core.Filter{IncludeDeprecated: true})
+	if err != nil {
+		return core.RepoStats{}, err
+	}
+
+	stats := core.RepoStats{
+		TotalRules:       len(rules),
+		RulesPerCategory: make(map[string]int),
+	}
+
+	for _, rule := range rules {
+		stats.RulesPerCategory[rule.Category]++
+
+		if len(rule.Examples) > 0 {
+			stats.RulesWithExamples++
+		}
+	}
+
+	return stats, nil
+}
+
+// handleListCategories processes the listcategories tool request.
+// It returns the declared categories and the categories of the rules encoded as JSON,
+// with their declared metadata and rule counts, restricted to the allowed categories.
+func (s *Service) handleListCategories(ctx context.Context, args ListCategoriesArgs) (*mcp.ToolResponse, error) {
+	logger := loggerFromContext(ctx)
+	ctx = core.WithClientID(ctx, args.Client)
+
+	var (
+		stats core.RepoStats
+		err   error
+	)
+
+	if len(s.config.AllowedCategories) > 0 {
+		stats, err = s.allowedStats(ctx)
+	} else {
+		stats, err = s.handler.Stats(ctx)
+	}
+
+	if err != nil {
+		logger.Debug("list categories failed", "error", err)
+		return nil, fmt.Errorf("get stats: %w", err)
+	}
+
+	names := slices.Collect(maps.Keys(stats.RulesPerCategory))
+	for _, category := range s.config.Categories {
+		if s.isAllowed(category.Name) {
+			names = append(names, category.Name)
+		}
+	}
+
+	slices.Sort(names)
+
+	categories := make([]categoryInfo, 0, len(names))
+
+	for _, name := range slices.Compact(names) {
+		info := categoryInfo{Name: name, Description: s.categorySummary(name)}
+		if declared, ok := s.declaredCategory(name); ok {
+			info.DisplayName = declared.DisplayName
+		}
+
+		for category, count := range stats.RulesPerCategory {
+			if core.MatchesCategory(category, name) {
+				info.Rules += count
+			}
+		}
+
+		categories = append(categories, info)
+	}
+
+	data, err := s.marshalJSON(categories)
+	if err != nil {
+		return nil, fmt.Errorf("marshal categories: %w", err)
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(string(data))), nil
+}
+
+// handleFingerprint processes the fingerprint tool request.
+// It returns the fingerprint of the rules encoded as JSON.
+func (s *Service) handleFingerprint(ctx context.Context, args FingerprintArgs) (*mcp.ToolResponse, error) {
+	logger := loggerFromContext(ctx)
+	ctx = core.WithClientID(ctx, args.Client)
+
+	fingerprint, err := s.handler.Fingerprint(ctx)
+	if err != nil {
+		logger.Debug("fingerprint failed", "error", err)
+		return nil, fmt.Errorf("get fingerprint: %w", err)
+	}
+
+	data, err := s.marshalJSON(map[string]string{"fingerprint": fingerprint})
+	if err != nil {
+		return nil, fmt.Errorf("marshal fingerprint: %w", err)
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(string(data))), nil
+}
+
+// handleRuleHits processes the rulehits tool request.
+// It returns the rule hit counters encoded as JSON.
+func (s *Service) handleRuleHits(_ context.Context, _ RuleHitsArgs) (*mcp.ToolResponse, error) {
+	data, err := s.marshalJSON(s.handler.RuleHits())
+	if err != nil {
+		return nil, fmt.Errorf("marshal rule hits: %w", err)
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(string(data))), nil
 }
 
 // handleCodeStyle processes the codestyle tool request.
 // It retrieves and formats code style rules based on the provided categories.
-func (s *Service) handleCodeStyle(args CodeStyleArgs) (*mcp.ToolResponse, error) {
-	slog.Debug("handling get_code_guidelines request", "categories", args.Categories)
+func (s *Service) handleCodeStyle(ctx context.Context, args CodeStyleArgs) (*mcp.ToolResponse, error) {
+	logger := loggerFromContext(ctx)
+	logger.Debug("handling get_code_guidelines request", "categories", args.Categories, "code_contains", args.CodeContains, "client", args.Client)
+	ctx = core.WithClientID(ctx, args.Client)
+
+	args = s.applyDefaults(args)
 
-	// Split categories by comma
-	categories := strings.Split(args.Categories, ",")
-	for i, cat := range categories {
-		categories[i] = strings.TrimSpace(cat)
+	if s.config.PrefixCategories {
+		resolved, err := s.resolveCategoryPrefixes(ctx, args.Categories)
+		if err != nil {
+			return nil, err
+		}
+
+		args.Categories = resolved
 	}
 
-	rules, err := s.handler.GetCodeStyle(context.Background(), categories)
+	requested, weights, _ := parseCategories(args.Categories) // checked by Validate
+
+	known, err := s.knownCategories(ctx, append(requested, splitCategories(args.ExcludeCategories)...))
 	if err != nil {
-		slog.Debug("get_rules_by_category failed", "error", err)
+		return nil, err
+	}
+
+	if err := args.Validate(known); err != nil {
+		logger.Debug("codestyle arguments are invalid", "error", err)
+		return nil, err
+	}
+
+	categories, err := s.restrictCategories(requested)
+	if err != nil {
+		logger.Debug("codestyle categories are not allowed", "error", err)
+		return nil, err
+	}
+
+	if s.config.StrictCategories {
+		if err := s.checkCategoriesExist(ctx, requested); err != nil {
+			logger.Debug("codestyle categories have no rules", "error", err)
+			return nil, err
+		}
+	}
+
+	filter := args.filter()
+	filter.CategoryWeights = weights
+
+	if excluded := splitCategories(args.ExcludeCategories); len(excluded) > 0 {
+		filter.ExcludeCategories = excluded
+	}
+
+	if filter.PerCategoryLimit > 0 {
+		// Fetch one extra rule per category to detect whether the limit cut results
+		filter.PerCategoryLimit++
+	}
+
+	rules, err := s.handler.GetCodeStyle(ctx, categories, filter)
+	if err != nil {
+		logger.Debug("get_rules_by_category failed", "error", err)
 		return nil, fmt.Errorf("get rules by category: %w", err)
 	}
 
-	slog.Debug("get_rules_by_category completed", "rules_count", len(rules))
+	broadened := false
+
+	if args.WidenOnInsufficient && filter.CodeContains != "" && len(core.LimitPerCategory(rules, args.PerCategoryLimit)) < args.MinResults {
+		logger.Debug("codestyle matched fewer rules than min_results, dropping code_contains", "rules_count", len(rules), "min_results", args.MinResults)
+
+		filter.CodeContains = ""
+
+		if rules, err = s.handler.GetCodeStyle(ctx, categories, filter); err != nil {
+			logger.Debug("get_rules_by_category failed", "error", err)
+			return nil, fmt.Errorf("get rules by category: %w", err)
+		}
+
+		broadened = true
+	}
+
+	limited := core.LimitPerCategory(rules, args.PerCategoryLimit)
+	truncated := len(limited) < len(rules)
+	rules = limited
+
+	logger.Debug("get_rules_by_category completed", "rules_count", len(rules), "truncated", truncated)
+
+	content, included, err := s.formatResponse(rules, args.Format, renderOptions{
+		includeExamples: s.includeExamples(args),
+		includeCategory: includeCategory(args, categories),
+		namesOnly:       args.NamesOnly,
+		tokenBudget:     modelTokenBudget(args.Model, s.config.DefaultTokenBudget),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if included < len(rules) {
+		logger.Debug("codestyle response truncated", "included", included, "rules_count", len(rules))
+		truncated = true
+	}
+
+	s.handler.RecordCodeStyle(ctx, categories, filter, rules[:included])
+
+	meta := codeStyleMetadata{
+		Matched:      included,
+		Categories:   categories,
+		Conflicts:    core.Conflicts(rules[:included]),
+		Truncated:    truncated,
+		Broadened:    broadened,
+		Insufficient: included < args.MinResults,
+	}
+
+	if args.Language != "" && included > 0 {
+		// All rules come from the same step of the language fallback chain
+		language := strings.ToLower(rules[0].Language)
+		meta.Language = &language
+	}
+
+	data, err := s.marshalJSON(meta)
+	if err != nil {
+		return nil, fmt.Errorf("marshal metadata: %w", err)
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(content), mcp.NewTextContent(string(data))), nil
+}
+
+// codeStyleMetadata describes a codestyle response. It is sent as a JSON
+// content block after the rules so that clients can decide whether to request more.
+type codeStyleMetadata struct {
+	Language     *string     `json:"language,omitempty"`
+	Categories   []string    `json:"categories"`
+	Conflicts    [][2]string `json:"conflicts,omitempty"`
+	Matched      int         `json:"matched"`
+	Truncated    bool        `json:"truncated"`
+	Broadened    bool        `json:"broadened,omitempty"`
+	Insufficient bool        `json:"insufficient,omitempty"`
+}
+
+// marshalJSON encodes v for a response, indented when Config.PrettyJSON is set.
+func (s *Service) marshalJSON(v any) ([]byte, error) {
+	if s.config.PrettyJSON {
+		return json.MarshalIndent(v, "", "  ")
+	}
+
+	return json.Marshal(v)
+}
+
+// renderOptions control how rules are rendered in responses.
+type renderOptions struct {
+	// includeExamples renders rule examples and templates, otherwise only a summary of each rule
+	includeExamples bool
+	// includeCategory precedes each rule with a "Category:" line in text output
+	includeCategory bool
+	// namesOnly renders each rule as a "name (category)" line in any format, see formatNames
+	namesOnly bool
+	// tokenBudget caps the response in tokens in addition to MaxResponseBytes, zero means no budget
+	tokenBudget int
+}
+
+// formatRules renders rules in the requested format.
+// An empty format defaults to the LLM-friendly text representation.
+// Returns ErrUnsupportedFormat for unknown formats.
+func formatRules(rules []core.Rule, format string, opts renderOptions) (string, error) {
+	sections, sep, err := renderRules(rules, format, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(sections, sep), nil
+}
+
+// formatResponse renders rules like formatRules, keeping the content within the
+// configured MaxResponseBytes and the token budget of opts, and prepends the
+// configured ResponsePreamble unless no rules or only their names are included.
+// It returns the content and the number of rules it includes.
+func (s *Service) formatResponse(rules []core.Rule, format string, opts renderOptions) (string, int, error) {
+	sections, sep, err := renderRules(rules, format, opts)
+	if err != nil {
+		return "", 0, err
+	}
+
+	content, included, err := capSections(sections, sep, s.responseLimit(opts.tokenBudget))
+	if err != nil {
+		return "", 0, err
+	}
+
+	if s.config.ResponsePreamble != "" && included > 0 && !opts.namesOnly {
+		content = s.config.ResponsePreamble + "\n\n" + content
+	}
+
+	return content, included, nil
+}
+
+// renderRules renders each rule as a separate section in the requested format
+// and returns the separator the sections are joined with.
+// Returns ErrUnsupportedFormat for unknown formats.
+func renderRules(rules []core.Rule, format string, opts renderOptions) (sections []string, sep string, err error) {
+	if opts.namesOnly {
+		if err := validateFormat(format); err != nil {
+			return nil, "", err
+		}
+
+		return formatNames(rules), "\n", nil
+	}
+
+	switch format {
+	case "", FormatText:
+		// Format rules in an LLM-friendly way
+		sections = make([]string, 0, len(rules))
+		for _, rule := range rules {
+			var formatted string
 
-	// Format rules in an LLM-friendly way
-	formattedRules := make([]string, 0, len(rules)*2) // Pre-allocate for rule and separator
+			switch {
+			case opts.includeExamples && opts.includeCategory:
+				formatted = rule.FormatForLLMWithCategory()
+			case opts.includeExamples:
+				formatted = rule.FormatForLLM()
+			case opts.includeCategory:
+				formatted = rule.FormatSummaryWithCategory()
+			default:
+				formatted = rule.FormatSummary()
+			}
+
+			sections = append(sections, formatted+"\n---") // Separator between rules
+		}
+
+		return sections, "\n", nil
+	case FormatMarkdown:
+		sections = make([]string, 0, len(rules))
+		for _, rule := range rules {
+			if !opts.includeExamples {
+				rule.Examples = nil
+				rule.Template = ""
+			}
+
+			sections = append(sections, rule.FormatMarkdown())
+		}
+
+		return sections, "\n", nil
+	default:
+		return nil, "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// formatNames renders each rule as a "name (category)" line, an index of the rules
+// that is much smaller than their content.
+func formatNames(rules []core.Rule) []string {
+	names := make([]string, 0, len(rules))
 	for _, rule := range rules {
-		// Include both the rule format and its LLM-friendly representation
-		formattedRules = append(formattedRules,
-			rule.FormatForLLM(),
-			"---") // Separator between rules
+		names = append(names, rule.Name+" ("+rule.Category+")")
+	}
+
+	return names
+}
+
+// capSections joins sections with sep. When the result exceeds maxBytes, only the
+// leading sections that fit together with a truncation notice are kept, so rules
+// are never cut in the middle. A maxBytes of zero or less disables the cap.
+// It returns the content and the number of sections it includes.
+// Returns ErrResponseTooLarge if not even the first section fits.
+func capSections(sections []string, sep string, maxBytes int) (string, int, error) {
+	content := strings.Join(sections, sep)
+	if maxBytes <= 0 || len(content) <= maxBytes {
+		return content, len(sections), nil
+	}
+
+	// size is the length of the first n sections joined, for n from 1 up
+	size := make([]int, len(sections)+1)
+	for i, section := range sections {
+		size[i+1] = size[i] + len(section)
+		if i > 0 {
+			size[i+1] += len(sep)
+		}
+	}
+
+	for n := len(sections) - 1; n > 0; n-- {
+		notice := fmt.Sprintf("[Response truncated: %d more rules omitted to stay within %d bytes]", len(sections)-n, maxBytes)
+
+		if size[n]+len(sep)+len(notice) <= maxBytes {
+			return strings.Join(sections[:n], sep) + sep + notice, n, nil
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: a single rule exceeds %d bytes", ErrResponseTooLarge, maxBytes)
+}
+
+// restrictCategories checks the requested categories against the configured allowlist.
+// The "*" wildcard is expanded to the allowed categories. Descendants of allowed categories
+// are allowed, and a parent of allowed categories is narrowed down to them.
+// Returns *ValidationError wrapping ErrCategoryNotAllowed for categories outside the allowlist.
+func (s *Service) restrictCategories(categories []string) ([]string, error) {
+	allowed := s.config.AllowedCategories
+	if len(allowed) == 0 {
+		return categories, nil
+	}
+
+	var issues []error
+
+	restricted := make([]string, 0, len(categories))
+
+	for _, cat := range categories {
+		switch {
+		case cat == core.AllCategories:
+			restricted = append(restricted, allowed...)
+		case slices.ContainsFunc(allowed, func(a string) bool { return core.MatchesCategory(cat, a) }):
+			restricted = append(restricted, cat)
+		default:
+			descendants := slices.DeleteFunc(slices.Clone(allowed), func(a string) bool { return !core.MatchesCategory(a, cat) })
+			if len(descendants) == 0 {
+				issues = append(issues, fmt.Errorf("%w: %s", ErrCategoryNotAllowed, cat))
+			}
+
+			restricted = append(restricted, descendants...)
+		}
+	}
+
+	if len(issues) > 0 {
+		return nil, &ValidationError{Issues: issues}
+	}
+
+	return slices.Compact(slices.Sorted(slices.Values(restricted))), nil
+}
+
+// knownCategories returns the top-level categories accepted for categories: the built-in
+// categories, the declared categories and the top-level categories of the repository rules.
+// The repository is only asked when one of categories is not nested in a built-in or
+// declared category.
+// Returns error if the repository stats cannot be read.
+func (s *Service) knownCategories(ctx context.Context, categories []string) (map[string]bool, error) {
+	known := maps.Clone(builtinCategories)
+	for _, category := range s.config.Categories {
+		known[categoryRoot(category.Name)] = true
+	}
+
+	if !slices.ContainsFunc(categories, func(cat string) bool { return !known[categoryRoot(cat)] }) {
+		return known, nil
+	}
+
+	stats, err := s.handler.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get stats: %w", err)
+	}
+
+	for category := range stats.RulesPerCategory {
+		known[categoryRoot(category)] = true
+	}
+
+	return known, nil
+}
+
+// resolveCategoryPrefixes rewrites the raw categories argument so that each category naming
+// no known category is replaced by all the known categories starting with it, keeping its
+// weight, e.g. "test:2" becomes "testing:2". Known categories are the built-in top-level
+// categories, the declared categories and the categories of the repository rules;
+// descendants of a match are left out, as the match selects them already. Categories
+// without any match are kept as is, to be reported by validation.
+// Returns error if the repository stats cannot be read.
+func (s *Service) resolveCategoryPrefixes(ctx context.Context, raw string) (string, error) {
+	if names, _, _ := parseCategories(raw); !slices.ContainsFunc(names, func(name string) bool { return !builtinCategories[name] }) {
+		return raw, nil
+	}
+
+	stats, err := s.handler.Stats(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get stats: %w", err)
+	}
+
+	known := make([]string, 0, len(builtinCategories)+len(stats.RulesPerCategory))
+	for category := range builtinCategories {
+		if category != core.AllCategories {
+			known = append(known, category)
+		}
+	}
+
+	known = append(known, slices.Collect(maps.Keys(stats.RulesPerCategory))...)
+	for _, category := range s.config.Categories {
+		known = append(known, category.Name)
+	}
+
+	slices.Sort(known)
+	known = slices.Compact(known)
+
+	entries := splitCategories(raw)
+	resolved := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		name, weight, weighted := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+
+		if name == "" || builtinCategories[name] || slices.ContainsFunc(known, func(k string) bool { return core.MatchesCategory(k, name) }) {
+			resolved = append(resolved, entry)
+			continue
+		}
+
+		var matches []string
+
+		for _, category := range known {
+			if strings.HasPrefix(category, name) && !slices.ContainsFunc(matches, func(m string) bool { return core.MatchesCategory(category, m) }) {
+				matches = append(matches, category)
+			}
+		}
+
+		if len(matches) == 0 {
+			resolved = append(resolved, entry)
+			continue
+		}
+
+		for _, match := range matches {
+			if weighted {
+				match += ":" + weight
+			}
+
+			resolved = append(resolved, match)
+		}
+	}
+
+	return strings.Join(resolved, ","), nil
+}
+
+// checkCategoriesExist returns error wrapping ErrCategoryNotFound, listing the categories
+// that match no rule at all, regardless of filters. The "*" wildcard always exists.
+func (s *Service) checkCategoriesExist(ctx context.Context, categories []string) error {
+	stats, err := s.handler.Stats(ctx)
+	if err != nil {
+		return fmt.Errorf("get stats: %w", err)
+	}
+
+	var missing []string
+
+	for _, cat := range categories {
+		if cat == core.AllCategories || slices.Contains(missing, cat) {
+			continue
+		}
+
+		found := false
+
+		for category := range stats.RulesPerCategory {
+			if core.MatchesCategory(category, cat) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, cat)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrCategoryNotFound, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// isAllowed reports whether category is one of the allowed categories or nested in one.
+// Every category is allowed when there is no allowlist.
+func (s *Service) isAllowed(category string) bool {
+	return len(s.config.AllowedCategories) == 0 ||
+		slices.ContainsFunc(s.config.AllowedCategories, func(a string) bool { return core.MatchesCategory(category, a) })
+}
+
+// allowedRules drops rules from categories outside the allowlist and their descendants and reports
+// the requested names left without any rule as missing, in request order.
+func (s *Service) allowedRules(rules []core.Rule, names []string) (allowed []core.Rule, missing []string) {
+	found := make(map[string]bool, len(rules))
+
+	for _, rule := range rules {
+		if s.isAllowed(rule.Category) {
+			allowed = append(allowed, rule)
+			found[rule.Name] = true
+		}
+	}
+
+	for _, name := range names {
+		if !found[name] && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+
+	return allowed, missing
+}
+
+// includeExamples reports whether examples should be included in the response.
+// The request argument takes precedence over the configuration, which defaults to true.
+func (s *Service) includeExamples(args CodeStyleArgs) bool {
+	if args.IncludeExamples != nil {
+		return *args.IncludeExamples
+	}
+
+	if s.config.IncludeExamples != nil {
+		return *s.config.IncludeExamples
+	}
+
+	return true
+}
+
+// includeCategory reports whether text output names the category of each rule:
+// as requested in args, or when rules of several categories may be returned otherwise.
+func includeCategory(args CodeStyleArgs, categories []string) bool {
+	if args.IncludeCategory != nil {
+		return *args.IncludeCategory
+	}
+
+	return len(categories) > 1 || slices.Contains(categories, core.AllCategories)
+}
+
+// applyDefaults returns args with the configured default categories and example
+// limit filled in when the request does not specify them.
+func (s *Service) applyDefaults(args CodeStyleArgs) CodeStyleArgs {
+	if len(splitCategories(args.Categories)) == 0 {
+		args.Categories = strings.Join(s.config.DefaultCategories, ",")
+	}
+
+	if args.MaxExamplesPerRule == 0 {
+		args.MaxExamplesPerRule = s.config.MaxExamplesPerRule
 	}
 
-	return mcp.NewToolResponse(mcp.NewTextContent(strings.Join(formattedRules, "\n"))), nil
+	return args
 }
@@ -1,13 +1,16 @@
 package api
 
 import (
+	"bufio"
 	"context"
-	"errors"
-	"fmt"
+	"encoding/json"
+	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
 	mcp "github.com/metoro-io/mcp-golang"
 	"github.com/metoro-io/mcp-golang/transport/stdio"
 	"github.com/stretchr/testify/assert"
@@ -19,14 +22,114 @@ func TestNew(t *testing.T) {
 	// Arrange
 	cfg := &Config{}
 	handler := NewMockToolHandler(t)
+	info := ServerInfo{Name: "test-server", Version: "1.2.3"}
 
 	// Act
-	svc := New(cfg, handler)
+	svc := New(cfg, handler, info)
 
 	// Assert
 	assert.NotNil(t, svc)
 	assert.Equal(t, cfg, svc.config)
 	assert.Equal(t, handler, svc.handler)
+	assert.Equal(t, info, svc.info)
+	assert.Len(t, svc.middlewares, len(DefaultMiddlewares()))
+}
+
+func TestNew_CustomMiddlewares(t *testing.T) {
+	noop := func(next Handler) Handler { return next }
+
+	svc := New(&Config{}, NewMockToolHandler(t), ServerInfo{}, noop, noop)
+
+	assert.Len(t, svc.middlewares, 2)
+}
+
+func TestNew_MaxConcurrentRequests(t *testing.T) {
+	svc := New(&Config{MaxConcurrentRequests: 2}, NewMockToolHandler(t), ServerInfo{})
+	assert.Len(t, svc.middlewares, len(DefaultMiddlewares())+1)
+
+	noop := func(next Handler) Handler { return next }
+	custom := make([]Middleware, 1, 2)
+	custom[0] = noop
+
+	svc = New(&Config{MaxConcurrentRequests: 2}, NewMockToolHandler(t), ServerInfo{}, custom...)
+	assert.Len(t, svc.middlewares, 2)
+	assert.Nil(t, custom[:2][1], "the caller's middlewares must not be modified")
+}
+
+func TestService_newServer_ServerInfo(t *testing.T) {
+	// Arrange
+	svc := New(&Config{}, NewMockToolHandler(t), ServerInfo{Name: "test-server", Version: "1.2.3"})
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+
+	t.Cleanup(func() {
+		_ = inWriter.Close()
+		_ = outReader.Close()
+	})
+
+	server := svc.newServer(newStdioTransport(inReader, outWriter, 0))
+	require.NoError(t, server.Serve())
+
+	// Act
+	_, err := inWriter.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}` + "\n"))
+	require.NoError(t, err)
+
+	line, err := bufio.NewReader(outReader).ReadString('\n')
+	require.NoError(t, err)
+
+	// Assert
+	var resp struct {
+		Result struct {
+			ServerInfo ServerInfo `json:"serverInfo"`
+		} `json:"result"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(line), &resp))
+	assert.Equal(t, "test-server", resp.Result.ServerInfo.Name)
+	assert.Equal(t, "1.2.3", resp.Result.ServerInfo.Version)
+}
+
+func TestService_newServer_LargeRequest(t *testing.T) {
+	// A request far larger than the bufio and bufio.Scanner default buffers must arrive intact
+	code := strings.Repeat("x := strconv.Itoa(1)\n", 10_000)
+
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().Recommend(mock.Anything, code, []string{core.AllCategories}, 0).
+		Return([]core.Rule{{Name: "rule1", Category: "code", Description: "First rule"}}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+
+	t.Cleanup(func() {
+		_ = inWriter.Close()
+		_ = outReader.Close()
+	})
+
+	server := svc.newServer(newStdioTransport(inReader, outWriter, 0))
+	require.NoError(t, svc.setupTools(context.Background(), server))
+	require.NoError(t, server.Serve())
+
+	request, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "recommend", "arguments": map[string]any{"code": code}},
+	})
+	require.NoError(t, err)
+	require.Greater(t, len(request), bufio.MaxScanTokenSize)
+
+	go func() {
+		_, _ = inWriter.Write(append(request, '\n'))
+	}()
+
+	line, err := bufio.NewReader(outReader).ReadString('\n')
+	require.NoError(t, err)
+
+	assert.Contains(t, line, "First rule")
+	assert.NotContains(t, line, `"error"`)
 }
 
 func TestService_setupTools(t *testing.T) {
@@ -51,11 +154,11 @@ func TestService_setupTools(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
-			svc := New(&Config{}, tt.handler)
+			svc := New(&Config{}, tt.handler, ServerInfo{})
 			server := mcp.NewServer(stdio.NewStdioServerTransport())
 
 			// Act
-			err := svc.setupTools(server)
+			err := svc.setupTools(context.Background(), server)
 
 			// Assert
 			if tt.wantErr {
@@ -90,7 +193,7 @@ func TestService_Run(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
-			svc := New(&Config{}, tt.handler)
+			svc := New(&Config{}, tt.handler, ServerInfo{})
 			ctx, cancel := context.WithCancel(context.Background())
 
 			// Act
@@ -129,7 +232,8 @@ func TestService_handleCodeStyle(t *testing.T) {
 			name: "successful handling",
 			handler: func() *MockToolHandler {
 				m := NewMockToolHandler(t)
-				m.EXPECT().GetCodeStyle(mock.Anything, []string{"testing"}).Return([]core.Rule{
+				m.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+				m.EXPECT().GetCodeStyle(mock.Anything, []string{"testing"}, core.Filter{}).Return([]core.Rule{
 					{
 						Name:        "test_rule",
 						Category:    "testing",
@@ -155,7 +259,7 @@ func TestService_handleCodeStyle(t *testing.T) {
 			name: "handler error",
 			handler: func() *MockToolHandler {
 				m := NewMockToolHandler(t)
-				m.EXPECT().GetCodeStyle(mock.Anything, []string{"testing"}).Return(nil, assert.AnError)
+				m.EXPECT().GetCodeStyle(mock.Anything, []string{"testing"}, core.Filter{}).Return(nil, assert.AnError)
 				return m
 			}(),
 			args: CodeStyleArgs{
@@ -168,7 +272,8 @@ func TestService_handleCodeStyle(t *testing.T) {
 			name: "empty rules",
 			handler: func() *MockToolHandler {
 				m := NewMockToolHandler(t)
-				m.EXPECT().GetCodeStyle(mock.Anything, []string{"testing"}).Return([]core.Rule{}, nil)
+				m.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+				m.EXPECT().GetCodeStyle(mock.Anything, []string{"testing"}, core.Filter{}).Return([]core.Rule{}, nil)
 				return m
 			}(),
 			args: CodeStyleArgs{
@@ -183,10 +288,10 @@ func TestService_handleCodeStyle(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
-			svc := New(&Config{}, tt.handler)
+			svc := New(&Config{}, tt.handler, ServerInfo{})
 
 			// Act
-			resp, err := svc.handleCodeStyle(tt.args)
+			resp, err := svc.handleCodeStyle(context.Background(), tt.args)
 
 			// Assert
 			if tt.wantErr {
@@ -201,7 +306,7 @@ func TestService_handleCodeStyle(t *testing.T) {
 
 			if tt.wantRules {
 				require.NotNil(t, resp.Content)
-				require.Len(t, resp.Content, 1)
+				require.Len(t, resp.Content, 2)
 				require.NotNil(t, resp.Content[0])
 
 				content := resp.Content[0].TextContent
@@ -220,78 +325,1577 @@ func TestService_handleCodeStyle(t *testing.T) {
 	}
 }
 
-func TestCodeStyleArgs_Validation(t *testing.T) {
+func TestService_applyDefaults(t *testing.T) {
 	tests := []struct {
-		name    string
-		args    CodeStyleArgs
-		wantErr bool
+		config   *Config
+		name     string
+		raw      string
+		expected string
 	}{
 		{
-			name: "valid args",
-			args: CodeStyleArgs{
-				Categories: "testing",
-			},
-			wantErr: false,
+			name:     "explicit categories",
+			config:   &Config{DefaultCategories: []string{"code"}},
+			raw:      " testing , documentation ",
+			expected: " testing , documentation ",
 		},
 		{
-			name: "multiple categories",
-			args: CodeStyleArgs{
-				Categories: "testing,documentation",
-			},
-			wantErr: false,
+			name:     "empty uses defaults",
+			config:   &Config{DefaultCategories: []string{"testing", "code"}},
+			raw:      " , ",
+			expected: "testing,code",
 		},
 		{
-			name: "empty categories",
-			args: CodeStyleArgs{
-				Categories: "",
-			},
-			wantErr: true,
+			name:     "empty without defaults",
+			config:   &Config{},
+			raw:      "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(tt.config, NewMockToolHandler(t), ServerInfo{})
+
+			args := svc.applyDefaults(CodeStyleArgs{Categories: tt.raw})
+
+			assert.Equal(t, tt.expected, args.Categories)
+		})
+	}
+}
+
+func TestService_applyDefaults_MaxExamplesPerRule(t *testing.T) {
+	svc := New(&Config{MaxExamplesPerRule: 2}, NewMockToolHandler(t), ServerInfo{})
+
+	assert.Equal(t, 2, svc.applyDefaults(CodeStyleArgs{Categories: "code"}).MaxExamplesPerRule)
+	assert.Equal(t, 5, svc.applyDefaults(CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{MaxExamplesPerRule: 5}}).MaxExamplesPerRule)
+}
+
+func TestService_handleCodeStyle_MaxExamplesPerRule(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{MaxExamplesPerRule: 1, CodeContains: "errorf"}).
+		Return([]core.Rule{{Name: "rule1", Category: "code", Description: "First rule"}}, nil)
+
+	svc := New(&Config{MaxExamplesPerRule: 3}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{CodeContains: "errorf", MaxExamplesPerRule: 1}})
+	require.NoError(t, err)
+
+	_, err = svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{MaxExamplesPerRule: -1}})
+	assert.ErrorIs(t, err, ErrNegativeMaxExamples)
+}
+
+func TestService_handleCodeStyle_InvalidArgs(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().Stats(mock.Anything).Return(core.RepoStats{RulesPerCategory: map[string]int{"security": 1}}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "testing,unknown", FilterArgs: FilterArgs{Format: "html"}})
+
+	assert.Nil(t, resp)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Issues, 2)
+	assert.ErrorIs(t, err, ErrInvalidCategory)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	assert.Contains(t, err.Error(), "invalid category: unknown")
+	assert.Contains(t, err.Error(), "unsupported format: html")
+}
+
+func TestService_handleCodeStyle_CategoriesRequired(t *testing.T) {
+	svc := New(&Config{}, NewMockToolHandler(t), ServerInfo{})
+
+	resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{})
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrCategoriesRequired)
+}
+
+func TestService_handleCodeStyle_StrictCategories(t *testing.T) {
+	stats := core.RepoStats{RulesPerCategory: map[string]int{"code/concurrency": 2, "testing": 1}}
+
+	tests := []struct {
+		name       string
+		categories string
+		wantErr    string
+		strict     bool
+	}{
+		{name: "lenient missing category", categories: "testing,code/concurency"},
+		{name: "strict existing categories", categories: "testing,code", strict: true},
+		{name: "strict all categories", categories: "*", strict: true},
+		{name: "strict missing categories", categories: "testing,code/concurency,documentation", strict: true, wantErr: "category has no rules: code/concurency, documentation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			if tt.strict {
+				handler.EXPECT().Stats(mock.Anything).Return(stats, nil)
+			}
+
+			if tt.wantErr == "" {
+				handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+				handler.EXPECT().GetCodeStyle(mock.Anything, splitCategories(tt.categories), core.Filter{}).Return([]core.Rule{}, nil)
+			}
+
+			svc := New(&Config{StrictCategories: tt.strict}, handler, ServerInfo{})
+
+			resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: tt.categories})
+
+			if tt.wantErr != "" {
+				assert.Nil(t, resp)
+				assert.ErrorIs(t, err, ErrCategoryNotFound)
+				assert.EqualError(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, resp)
+		})
+	}
+}
+
+func TestService_resolveCategoryPrefixes(t *testing.T) {
+	stats := core.RepoStats{RulesPerCategory: map[string]int{
+		"testing":          1,
+		"testing/unit":     1,
+		"code/concurrency": 2,
+		"code/context":     1,
+		"documentation":    1,
+	}}
+
+	tests := []struct {
+		name      string
+		raw       string
+		want      string
+		wantStats bool
+	}{
+		{name: "exact top-level categories", raw: "testing, code", want: "testing, code"},
+		{name: "wildcard", raw: "*", want: "*"},
+		{name: "top-level prefix", raw: "test", want: "testing", wantStats: true},
+		{name: "weighted prefix", raw: "test:2,code", want: "testing:2,code", wantStats: true},
+		{name: "several matches", raw: "t", want: "template,testing", wantStats: true},
+		{name: "nested prefix", raw: "code/con", want: "code/concurrency,code/context", wantStats: true},
+		{name: "exact nested category", raw: "code/concurrency", want: "code/concurrency", wantStats: true},
+		{name: "parent of nested category", raw: "testing/unit", want: "testing/unit", wantStats: true},
+		{name: "no match", raw: "xyz,doc", want: "xyz,documentation", wantStats: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			if tt.wantStats {
+				handler.EXPECT().Stats(mock.Anything).Return(stats, nil)
+			}
+
+			svc := New(&Config{PrefixCategories: true}, handler, ServerInfo{})
+
+			got, err := svc.resolveCategoryPrefixes(context.Background(), tt.raw)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().Stats(mock.Anything).Return(core.RepoStats{}, assert.AnError)
+
+	_, err := New(&Config{PrefixCategories: true}, handler, ServerInfo{}).resolveCategoryPrefixes(context.Background(), "test")
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestService_handleCodeStyle_RepositoryCategory(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().Stats(mock.Anything).Return(core.RepoStats{RulesPerCategory: map[string]int{"security/crypto": 1, "code": 2}}, nil)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"security"}, core.Filter{ExcludeCategories: []string{"security/legacy"}}).
+		Return([]core.Rule{{Name: "constant_time_compare", Category: "security/crypto", Description: "Compare secrets in constant time"}}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "security", ExcludeCategories: "security/legacy"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Content[0].TextContent.Text, "Compare secrets in constant time")
+}
+
+func TestService_handleCodeStyle_DeclaredCategory(t *testing.T) {
+	// Stats is not expected: declared categories are accepted even without rules
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"security/crypto"}, core.Filter{}).Return(nil, nil)
+
+	svc := New(&Config{Categories: []Category{{Name: "security"}}}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "security/crypto"})
+	require.NoError(t, err)
+}
+
+func TestService_handleCodeStyle_PrefixCategoryFromRepository(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().Stats(mock.Anything).Return(core.RepoStats{RulesPerCategory: map[string]int{"security": 1}}, nil)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"security"}, core.Filter{}).
+		Return([]core.Rule{{Name: "constant_time_compare", Category: "security", Description: "Compare secrets in constant time"}}, nil)
+
+	svc := New(&Config{PrefixCategories: true}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "sec"})
+	require.NoError(t, err)
+}
+
+func TestService_handleCodeStyle_PrefixCategories(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().Stats(mock.Anything).Return(core.RepoStats{RulesPerCategory: map[string]int{"testing": 1}}, nil)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"testing"}, core.Filter{CategoryWeights: map[string]int{"testing": 2}}).
+		Return([]core.Rule{{Name: "table_tests", Category: "testing", Description: "Use table tests"}}, nil)
+
+	svc := New(&Config{PrefixCategories: true}, handler, ServerInfo{})
+
+	resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "test:2"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"categories":["testing"],"matched":1,"truncated":false}`, resp.Content[1].TextContent.Text)
+
+	exact := NewMockToolHandler(t)
+	exact.EXPECT().Stats(mock.Anything).Return(core.RepoStats{RulesPerCategory: map[string]int{"testing": 1}}, nil)
+
+	_, err = New(&Config{}, exact, ServerInfo{}).handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "test"})
+	assert.ErrorIs(t, err, ErrInvalidCategory, "exact matching is the default")
+}
+
+func TestService_handleCodeStyle_CategoryWeights(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"testing", "code"}, core.Filter{CategoryWeights: map[string]int{"testing": 2}}).
+		Return([]core.Rule{}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "testing:2,code"})
+	require.NoError(t, err)
+
+	_, err = svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "testing:,code"})
+	assert.ErrorIs(t, err, ErrInvalidCategoryWeight)
+}
+
+func TestService_handleCodeStyle_CodeContains(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{CodeContains: "errgroup"}).Return([]core.Rule{}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{CodeContains: "errgroup"}})
+
+	require.NoError(t, err)
+}
+
+func TestService_handleCodeStyle_RecordsReturnedRules(t *testing.T) {
+	all := []core.Rule{
+		{Name: "errgroup", Category: "code", Description: "Use errgroup"},
+		{Name: "early_return", Category: "code", Description: "Return early"},
+	}
+
+	handler := NewMockToolHandler(t)
+	// One extra rule is fetched to detect truncation, and the query is widened once
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{CodeContains: "errgroup", PerCategoryLimit: 2}).Return(all[:1], nil).Once()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{PerCategoryLimit: 2}).Return(all, nil).Once()
+	handler.EXPECT().RecordCodeStyle(mock.Anything, []string{"code"}, core.Filter{PerCategoryLimit: 2}, all[:1]).Once()
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{
+		Categories: "code",
+		FilterArgs: FilterArgs{
+			CodeContains:        "errgroup",
+			PerCategoryLimit:    1,
+			MinResults:          2,
+			WidenOnInsufficient: true,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestService_handleCodeStyle_MinResults(t *testing.T) {
+	all := []core.Rule{
+		{Name: "errgroup", Category: "code", Description: "Use errgroup"},
+		{Name: "early_return", Category: "code", Description: "Return early"},
+	}
+	matching := all[:1]
+
+	tests := []struct {
+		name         string
+		wantMeta     string
+		wantContains []string
+		args         CodeStyleArgs
+		widen        bool
+	}{
+		{
+			name:         "enough rules",
+			args:         CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{CodeContains: "errgroup", MinResults: 1, WidenOnInsufficient: true}},
+			wantMeta:     `{"categories":["code"],"matched":1,"truncated":false}`,
+			wantContains: []string{"Use errgroup"},
 		},
 		{
-			name: "invalid category",
-			args: CodeStyleArgs{
-				Categories: "invalid",
-			},
-			wantErr: true,
+			name:         "too few rules without widening",
+			args:         CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{CodeContains: "errgroup", MinResults: 2}},
+			wantMeta:     `{"categories":["code"],"matched":1,"truncated":false,"insufficient":true}`,
+			wantContains: []string{"Use errgroup"},
+		},
+		{
+			name:         "too few rules with widening",
+			args:         CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{CodeContains: "errgroup", MinResults: 2, WidenOnInsufficient: true}},
+			widen:        true,
+			wantMeta:     `{"categories":["code"],"matched":2,"truncated":false,"broadened":true}`,
+			wantContains: []string{"Use errgroup", "Return early"},
+		},
+		{
+			name:         "still too few rules after widening",
+			args:         CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{CodeContains: "errgroup", MinResults: 3, WidenOnInsufficient: true}},
+			widen:        true,
+			wantMeta:     `{"categories":["code"],"matched":2,"truncated":false,"broadened":true,"insufficient":true}`,
+			wantContains: []string{"Use errgroup", "Return early"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Act
-			err := tt.args.Validate()
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{CodeContains: "errgroup"}).Return(matching, nil)
 
-			// Assert
-			if tt.wantErr {
-				assert.Error(t, err)
-				return
+			if tt.widen {
+				handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+				handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{}).Return(all, nil)
 			}
 
-			assert.NoError(t, err)
+			svc := New(&Config{}, handler, ServerInfo{})
+
+			resp, err := svc.handleCodeStyle(context.Background(), tt.args)
+
+			require.NoError(t, err)
+			require.Len(t, resp.Content, 2)
+			assert.JSONEq(t, tt.wantMeta, resp.Content[1].TextContent.Text)
+
+			for _, want := range tt.wantContains {
+				assert.Contains(t, resp.Content[0].TextContent.Text, want)
+			}
+		})
+	}
+
+	_, err := New(&Config{}, NewMockToolHandler(t), ServerInfo{}).handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{MinResults: -1}})
+	assert.ErrorIs(t, err, ErrNegativeMinResults)
+}
+
+func TestService_handleCodeStyle_PerCategoryLimit(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{PerCategoryLimit: 3}).Return([]core.Rule{}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{PerCategoryLimit: 2}})
+
+	require.NoError(t, err)
+}
+
+func TestService_handleCodeStyle_Metadata(t *testing.T) {
+	rules := []core.Rule{
+		{Name: "rule1", Category: "code", Description: "First rule"},
+		{Name: "rule2", Category: "code", Description: "Second rule"},
+		{Name: "rule3", Category: "testing", Description: "Third rule"},
+	}
+
+	tests := []struct {
+		name         string
+		wantMeta     string
+		wantContains []string
+		wantMissing  []string
+		limit        int
+	}{
+		{
+			name:         "without limit",
+			limit:        0,
+			wantMeta:     `{"categories":["code","testing"],"matched":3,"truncated":false}`,
+			wantContains: []string{"First rule", "Second rule", "Third rule"},
+		},
+		{
+			name:         "limit cuts results",
+			limit:        1,
+			wantMeta:     `{"categories":["code","testing"],"matched":2,"truncated":true}`,
+			wantContains: []string{"First rule", "Third rule"},
+			wantMissing:  []string{"Second rule"},
+		},
+		{
+			name:         "limit not reached",
+			limit:        2,
+			wantMeta:     `{"categories":["code","testing"],"matched":3,"truncated":false}`,
+			wantContains: []string{"First rule", "Second rule", "Third rule"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := core.Filter{}
+			if tt.limit > 0 {
+				filter.PerCategoryLimit = tt.limit + 1
+			}
+
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code", "testing"}, filter).
+				Return(core.LimitPerCategory(rules, filter.PerCategoryLimit), nil)
+
+			svc := New(&Config{}, handler, ServerInfo{})
+
+			resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code,testing", FilterArgs: FilterArgs{PerCategoryLimit: tt.limit}})
+
+			require.NoError(t, err)
+			require.Len(t, resp.Content, 2)
+			assert.JSONEq(t, tt.wantMeta, resp.Content[1].TextContent.Text)
+
+			text := resp.Content[0].TextContent.Text
+			for _, want := range tt.wantContains {
+				assert.Contains(t, text, want)
+			}
+
+			for _, missing := range tt.wantMissing {
+				assert.NotContains(t, text, missing)
+			}
 		})
 	}
 }
 
-// Helper function to add validation to CodeStyleArgs
-func (a *CodeStyleArgs) Validate() error {
-	if a.Categories == "" {
-		return errors.New("categories is required")
+func TestService_handleCodeStyle_Conflicts(t *testing.T) {
+	rules := []core.Rule{
+		{Name: "early_return", Category: "code", Description: "Return early", ConflictsWith: []string{"single_exit"}},
+		{Name: "single_exit", Category: "code", Description: "Return once"},
+	}
+
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{}).Return(rules, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code"})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Content, 2)
+	assert.JSONEq(t, `{"categories":["code"],"matched":2,"truncated":false,"conflicts":[["early_return","single_exit"]]}`, resp.Content[1].TextContent.Text)
+}
+
+func TestService_handleCodeStyle_IncludeCategory(t *testing.T) {
+	enabled, disabled := true, false
+
+	rules := []core.Rule{
+		{Name: "rule1", Category: "code", Description: "First rule"},
+	}
+
+	tests := []struct {
+		include    *bool
+		name       string
+		categories string
+		want       bool
+	}{
+		{name: "single category", categories: "code", want: false},
+		{name: "several categories", categories: "code,testing", want: true},
+		{name: "all categories", categories: "*", want: true},
+		{name: "forced on", categories: "code", include: &enabled, want: true},
+		{name: "forced off", categories: "code,testing", include: &disabled, want: false},
 	}
 
-	// Split and validate each category
-	validCategories := map[string]bool{
-		"documentation": true,
-		"testing":       true,
-		"code":          true,
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			handler.EXPECT().GetCodeStyle(mock.Anything, mock.Anything, core.Filter{}).Return(rules, nil)
+
+			svc := New(&Config{}, handler, ServerInfo{})
+
+			resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: tt.categories, IncludeCategory: tt.include})
+			require.NoError(t, err)
+
+			text := resp.Content[0].TextContent.Text
+			if tt.want {
+				assert.True(t, strings.HasPrefix(text, "Category: code\nDescription: First rule"), text)
+			} else {
+				assert.NotContains(t, text, "Category:")
+			}
+		})
 	}
+}
 
-	categories := strings.Split(a.Categories, ",")
-	for _, cat := range categories {
-		cat = strings.TrimSpace(cat)
-		if !validCategories[cat] {
-			return fmt.Errorf("invalid category: %s", cat)
-		}
+func TestService_handleCodeStyle_ExcludeCategories(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"*"}, core.Filter{ExcludeCategories: []string{"documentation", "code/concurrency"}}).
+		Return([]core.Rule{{Name: "table_tests", Category: "testing", Description: "Use table tests"}}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "*", ExcludeCategories: "documentation, code/concurrency"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"categories":["*"],"matched":1,"truncated":false}`, resp.Content[1].TextContent.Text)
+}
+
+func TestService_handleCodeStyle_Language(t *testing.T) {
+	tests := []struct {
+		name  string
+		want  string
+		rules []core.Rule
+	}{
+		{
+			name:  "fallback language",
+			rules: []core.Rule{{Name: "const_over_let", Category: "code", Description: "Prefer const", Language: "JavaScript"}},
+			want:  `{"categories":["code"],"language":"javascript","matched":1,"truncated":false}`,
+		},
+		{
+			name:  "rules without a language",
+			rules: []core.Rule{{Name: "early_return", Category: "code", Description: "Return early"}},
+			want:  `{"categories":["code"],"language":"","matched":1,"truncated":false}`,
+		},
+		{
+			name: "no rules",
+			want: `{"categories":["code"],"matched":0,"truncated":false}`,
+		},
 	}
 
-	return nil
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{Language: "typescript"}).Return(tt.rules, nil)
+
+			svc := New(&Config{}, handler, ServerInfo{})
+
+			resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{Language: "typescript"}})
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, resp.Content[1].TextContent.Text)
+		})
+	}
+}
+
+func TestService_handleCodeStyle_NamesOnly(t *testing.T) {
+	rules := []core.Rule{
+		{Name: "early_return", Category: "code", Description: "Return early", Examples: []core.Example{{Code: "return nil"}}},
+		{Name: "table_tests", Category: "testing", Description: "Use table tests"},
+	}
+
+	for _, format := range []string{"", FormatText, FormatMarkdown} {
+		t.Run("format "+format, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code", "testing"}, core.Filter{}).Return(rules, nil)
+
+			svc := New(&Config{}, handler, ServerInfo{})
+
+			resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code,testing", FilterArgs: FilterArgs{Format: format, NamesOnly: true}})
+
+			require.NoError(t, err)
+			require.Len(t, resp.Content, 2)
+			assert.Equal(t, "early_return (code)\ntable_tests (testing)", resp.Content[0].TextContent.Text)
+			assert.JSONEq(t, `{"categories":["code","testing"],"matched":2,"truncated":false}`, resp.Content[1].TextContent.Text)
+		})
+	}
+
+	_, err := New(&Config{}, NewMockToolHandler(t), ServerInfo{}).
+		handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{Format: "html", NamesOnly: true}})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestService_handleCodeStyle_MinSeverity(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{MinSeverity: core.SeverityMust}).Return([]core.Rule{}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{MinSeverity: core.SeverityMust}})
+
+	require.NoError(t, err)
+}
+
+func TestService_handleCodeStyle_UpdatedSince(t *testing.T) {
+	filter := core.Filter{
+		UpdatedSince: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		SortBy:       core.SortUpdated,
+	}
+
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, filter).Return([]core.Rule{}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{
+		Categories: "code",
+		FilterArgs: FilterArgs{
+			UpdatedSince: "2024-01-02T15:04:05Z",
+			Sort:         core.SortUpdated,
+		},
+	})
+
+	require.NoError(t, err)
+}
+
+func TestService_handleCodeStyle_IncludeDeprecated(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{IncludeDeprecated: true}).Return([]core.Rule{}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{IncludeDeprecated: true}})
+
+	require.NoError(t, err)
+}
+
+func TestService_handleCodeStyle_WithExamplesOnly(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{WithExamplesOnly: true}).Return([]core.Rule{}, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	_, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{WithExamplesOnly: true}})
+
+	require.NoError(t, err)
+}
+
+func TestService_handleCodeStyle_IncludeExamples(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		config       *Config
+		argValue     *bool
+		name         string
+		wantExamples bool
+	}{
+		{
+			name:         "default includes examples",
+			config:       &Config{},
+			wantExamples: true,
+		},
+		{
+			name:         "config disables examples",
+			config:       &Config{IncludeExamples: &disabled},
+			wantExamples: false,
+		},
+		{
+			name:         "argument overrides config",
+			config:       &Config{IncludeExamples: &disabled},
+			argValue:     &enabled,
+			wantExamples: true,
+		},
+		{
+			name:         "argument disables examples",
+			config:       &Config{},
+			argValue:     &disabled,
+			wantExamples: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{}).Return([]core.Rule{
+				{
+					Name:        "test_rule",
+					Category:    "code",
+					Description: "Test rule",
+					Examples:    []core.Example{{Description: "Example", Code: "test code"}},
+				},
+			}, nil)
+
+			svc := New(tt.config, handler, ServerInfo{})
+
+			resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{IncludeExamples: tt.argValue}})
+			require.NoError(t, err)
+			require.Len(t, resp.Content, 2)
+
+			text := resp.Content[0].TextContent.Text
+			assert.Contains(t, text, "Test rule")
+
+			if tt.wantExamples {
+				assert.Contains(t, text, "test code")
+			} else {
+				assert.NotContains(t, text, "test code")
+			}
+		})
+	}
+}
+
+func TestService_handleStats_AllowedCategories(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"testing", "code/concurrency"}, core.Filter{IncludeDeprecated: true}).Return([]core.Rule{
+		{Name: "table_tests", Category: "testing", Examples: []core.Example{{Code: "tests := []struct{}{}"}}},
+		{Name: "mutex_naming", Category: "code/concurrency"},
+	}, nil)
+
+	svc := New(&Config{AllowedCategories: []string{"testing", "code/concurrency"}}, handler, ServerInfo{})
+
+	resp, err := svc.handleStats(context.Background(), StatsArgs{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"rules_per_category":{"code/concurrency":1,"testing":1},"total_rules":2,"rules_with_examples":1}`, resp.Content[0].TextContent.Text)
+}
+
+func TestService_handleListCategories(t *testing.T) {
+	categories := []Category{
+		{Name: "code", DisplayName: "Code", Description: "Code organization"},
+		{Name: "security", DisplayName: "Security"},
+		{Name: "template/service"},
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		allowed []string
+	}{
+		{
+			name: "all categories",
+			want: `[
+				{"name":"code","display_name":"Code","description":"Code organization","rules":3},
+				{"name":"code/concurrency","description":"code rules on concurrency","rules":1},
+				{"name":"security","display_name":"Security","description":"security rules","rules":0},
+				{"name":"template/service","description":"template rules on service","rules":0},
+				{"name":"testing","description":"` + categorySummaries["testing"] + `","rules":1}
+			]`,
+		},
+		{
+			name:    "allowed categories",
+			allowed: []string{"code/concurrency", "security"},
+			want: `[
+				{"name":"code/concurrency","description":"code rules on concurrency","rules":1},
+				{"name":"security","display_name":"Security","description":"security rules","rules":0}
+			]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+
+			if len(tt.allowed) > 0 {
+				handler.EXPECT().GetCodeStyle(mock.Anything, tt.allowed, core.Filter{IncludeDeprecated: true}).
+					Return([]core.Rule{{Name: "mutex_naming", Category: "code/concurrency"}}, nil)
+			} else {
+				handler.EXPECT().Stats(mock.Anything).
+					Return(core.RepoStats{TotalRules: 4, RulesPerCategory: map[string]int{"code": 2, "code/concurrency": 1, "testing": 1}}, nil)
+			}
+
+			svc := New(&Config{Categories: categories, AllowedCategories: tt.allowed}, handler, ServerInfo{})
+
+			resp, err := svc.handleListCategories(context.Background(), ListCategoriesArgs{})
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, resp.Content[0].TextContent.Text)
+		})
+	}
+}
+
+func TestService_handleListCategories_Error(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().Stats(mock.Anything).Return(core.RepoStats{}, assert.AnError)
+
+	_, err := New(&Config{}, handler, ServerInfo{}).handleListCategories(context.Background(), ListCategoriesArgs{})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestService_handleStats(t *testing.T) {
+	tests := []struct {
+		handlerErr error
+		name       string
+		want       string
+		stats      core.RepoStats
+		wantErr    bool
+	}{
+		{
+			name: "successful handling",
+			stats: core.RepoStats{
+				TotalRules:        3,
+				RulesPerCategory:  map[string]int{"code": 2, "testing": 1},
+				RulesWithExamples: 2,
+			},
+			want: `{"rules_per_category":{"code":2,"testing":1},"total_rules":3,"rules_with_examples":2}`,
+		},
+		{
+			name:       "handler error",
+			handlerErr: assert.AnError,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().Stats(mock.Anything).Return(tt.stats, tt.handlerErr)
+
+			svc := New(&Config{}, handler, ServerInfo{})
+
+			resp, err := svc.handleStats(context.Background(), StatsArgs{})
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.handlerErr)
+				assert.Nil(t, resp)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, resp.Content, 1)
+			assert.JSONEq(t, tt.want, resp.Content[0].TextContent.Text)
+		})
+	}
+}
+
+func TestService_handleFingerprint(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().Fingerprint(mock.Anything).Return("abc123", nil).Once()
+	handler.EXPECT().Fingerprint(mock.Anything).Return("", assert.AnError).Once()
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	resp, err := svc.handleFingerprint(context.Background(), FingerprintArgs{})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Content, 1)
+	assert.JSONEq(t, `{"fingerprint":"abc123"}`, resp.Content[0].TextContent.Text)
+
+	resp, err = svc.handleFingerprint(context.Background(), FingerprintArgs{Client: "team-a"})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, resp)
+}
+
+func TestService_handleRuleHits(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RuleHits().Return(map[string]int{"table_tests": 2, "error_wrapping": 5})
+
+	svc := New(&Config{}, handler, ServerInfo{})
+
+	resp, err := svc.handleRuleHits(context.Background(), RuleHitsArgs{})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Content, 1)
+	assert.JSONEq(t, `{"error_wrapping":5,"table_tests":2}`, resp.Content[0].TextContent.Text)
+}
+
+func TestService_PrettyJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		prettyJSON bool
+	}{
+		{name: "compact by default", prettyJSON: false},
+		{name: "indented", prettyJSON: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			handler.EXPECT().Stats(mock.Anything).Return(core.RepoStats{TotalRules: 1, RulesPerCategory: map[string]int{"code": 1}}, nil)
+			handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{}).Return([]core.Rule{}, nil)
+
+			svc := New(&Config{PrettyJSON: tt.prettyJSON}, handler, ServerInfo{})
+
+			stats, err := svc.handleStats(context.Background(), StatsArgs{})
+			require.NoError(t, err)
+
+			codeStyle, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code"})
+			require.NoError(t, err)
+			require.Len(t, codeStyle.Content, 2)
+
+			for _, text := range []string{stats.Content[0].TextContent.Text, codeStyle.Content[1].TextContent.Text} {
+				if tt.prettyJSON {
+					assert.Contains(t, text, "\n  \"")
+				} else {
+					assert.NotContains(t, text, "\n")
+					assert.NotContains(t, text, "  ")
+				}
+			}
+		})
+	}
+}
+
+func TestService_marshalJSON_UnsupportedType(t *testing.T) {
+	for _, prettyJSON := range []bool{false, true} {
+		svc := New(&Config{PrettyJSON: prettyJSON}, NewMockToolHandler(t), ServerInfo{})
+
+		assert.NotPanics(t, func() {
+			_, err := svc.marshalJSON(map[string]any{"value": make(chan int)})
+
+			var typeErr *json.UnsupportedTypeError
+			assert.ErrorAs(t, err, &typeErr)
+		})
+	}
+}
+
+func TestService_handleGetRules(t *testing.T) {
+	rules := []core.Rule{
+		{Name: "rule2", Category: "code", Description: "Second rule"},
+		{Name: "rule1", Category: "testing", Description: "First rule"},
+	}
+
+	tests := []struct {
+		handlerErr  error
+		wantErr     error
+		name        string
+		args        GetRulesArgs
+		missing     []string
+		wantContent []string
+		callHandler bool
+	}{
+		{
+			name:        "all rules found",
+			args:        GetRulesArgs{Names: "rule2, rule1"},
+			callHandler: true,
+			wantContent: []string{"Second rule"},
+		},
+		{
+			name:        "missing rules reported separately",
+			args:        GetRulesArgs{Names: "rule2,stale,rule1"},
+			missing:     []string{"stale"},
+			callHandler: true,
+			wantContent: []string{"Second rule", "Missing rules: stale"},
+		},
+		{
+			name:    "names required",
+			args:    GetRulesArgs{},
+			wantErr: ErrNamesRequired,
+		},
+		{
+			name:        "handler error",
+			args:        GetRulesArgs{Names: "rule1"},
+			callHandler: true,
+			handlerErr:  assert.AnError,
+			wantErr:     assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			if tt.callHandler {
+				handler.EXPECT().RecordByNames(mock.Anything, mock.Anything, mock.Anything).Maybe()
+				handler.EXPECT().GetByNames(mock.Anything, splitCategories(tt.args.Names)).Return(rules, tt.missing, tt.handlerErr)
+			}
+
+			svc := New(&Config{}, handler, ServerInfo{})
+
+			resp, err := svc.handleGetRules(context.Background(), tt.args)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, resp)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, resp.Content, len(tt.wantContent))
+
+			for i, want := range tt.wantContent {
+				assert.Contains(t, resp.Content[i].TextContent.Text, want)
+			}
+
+			text := resp.Content[0].TextContent.Text
+			assert.Less(t, strings.Index(text, "Second rule"), strings.Index(text, "First rule"), "rules should keep handler order")
+		})
+	}
+}
+
+func TestService_handleRecommend(t *testing.T) {
+	rules := []core.Rule{
+		{Name: "error_wrapping", Category: "code", Description: "Wrap errors with fmt.Errorf"},
+		{Name: "error_checks", Category: "code", Description: "Check errors with errors.Is"},
+	}
+
+	tests := []struct {
+		handlerErr     error
+		wantErr        error
+		name           string
+		args           RecommendArgs
+		allowed        []string
+		wantCategories []string
+		callHandler    bool
+	}{
+		{
+			name:           "recommended rules",
+			args:           RecommendArgs{Code: `return fmt.Errorf("load: %w", err)`, Limit: 2},
+			callHandler:    true,
+			wantCategories: []string{core.AllCategories},
+		},
+		{
+			name:           "restricted to allowed categories",
+			args:           RecommendArgs{Code: `return fmt.Errorf("load: %w", err)`},
+			allowed:        []string{"testing", "code"},
+			callHandler:    true,
+			wantCategories: []string{"code", "testing"},
+		},
+		{
+			name:    "code required",
+			args:    RecommendArgs{Code: "  "},
+			wantErr: ErrCodeRequired,
+		},
+		{
+			name:    "negative limit",
+			args:    RecommendArgs{Code: "x", Limit: -1},
+			wantErr: ErrNegativeRecommendLimit,
+		},
+		{
+			name:           "handler error",
+			args:           RecommendArgs{Code: "x"},
+			callHandler:    true,
+			wantCategories: []string{core.AllCategories},
+			handlerErr:     assert.AnError,
+			wantErr:        assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			if tt.callHandler {
+				handler.EXPECT().Recommend(mock.Anything, tt.args.Code, tt.wantCategories, tt.args.Limit).Return(rules, tt.handlerErr)
+			}
+
+			svc := New(&Config{AllowedCategories: tt.allowed}, handler, ServerInfo{})
+
+			resp, err := svc.handleRecommend(context.Background(), tt.args)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, resp)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, resp.Content, 1)
+
+			text := resp.Content[0].TextContent.Text
+			assert.Less(t, strings.Index(text, "Wrap errors"), strings.Index(text, "Check errors"), "rules should keep handler order")
+		})
+	}
+}
+
+func TestService_handleRenderTemplate(t *testing.T) {
+	tests := []struct {
+		wantErr     error
+		name        string
+		args        RenderTemplateArgs
+		errText     string
+		expected    string
+		rules       []core.Rule
+		allowed     []string
+		callHandler bool
+	}{
+		{
+			name: "rendered template",
+			args: RenderTemplateArgs{Rule: "scaffold", Variables: map[string]string{"pkg": "users"}},
+			rules: []core.Rule{
+				{Name: "scaffold", Category: "code"},
+				{Name: "scaffold", Category: "template", Template: "package {{.pkg}}\n"},
+			},
+			callHandler: true,
+			expected:    "package users\n",
+		},
+		{
+			name:    "rule required",
+			args:    RenderTemplateArgs{Rule: " "},
+			wantErr: ErrRuleRequired,
+		},
+		{
+			name:        "unknown rule",
+			args:        RenderTemplateArgs{Rule: "scaffold"},
+			callHandler: true,
+			wantErr:     ErrRuleNotFound,
+		},
+		{
+			name:        "rule outside allowed categories",
+			args:        RenderTemplateArgs{Rule: "scaffold"},
+			rules:       []core.Rule{{Name: "scaffold", Category: "template", Template: "package main\n"}},
+			allowed:     []string{"code"},
+			callHandler: true,
+			wantErr:     ErrRuleNotFound,
+		},
+		{
+			name:        "rule without template",
+			args:        RenderTemplateArgs{Rule: "scaffold"},
+			rules:       []core.Rule{{Name: "scaffold", Category: "code"}},
+			callHandler: true,
+			wantErr:     core.ErrNoTemplate,
+		},
+		{
+			name:        "missing variable",
+			args:        RenderTemplateArgs{Rule: "scaffold"},
+			rules:       []core.Rule{{Name: "scaffold", Category: "template", Template: "package {{.pkg}}\n"}},
+			callHandler: true,
+			errText:     `render template of rule scaffold: execute template`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			if tt.callHandler {
+				handler.EXPECT().GetByNames(mock.Anything, []string{tt.args.Rule}).Return(tt.rules, nil, nil)
+			}
+
+			if tt.expected != "" {
+				// Only the rendered rule is recorded, not every rule sharing its name
+				handler.EXPECT().RecordByNames(mock.Anything, []string{tt.args.Rule}, tt.rules[1:])
+			}
+
+			svc := New(&Config{AllowedCategories: tt.allowed}, handler, ServerInfo{})
+
+			resp, err := svc.handleRenderTemplate(context.Background(), tt.args)
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, resp)
+			case tt.errText != "":
+				assert.ErrorContains(t, err, tt.errText)
+				assert.Nil(t, resp)
+			default:
+				require.NoError(t, err)
+				require.Len(t, resp.Content, 1)
+				assert.Equal(t, tt.expected, resp.Content[0].TextContent.Text)
+			}
+		})
+	}
+}
+
+func TestService_ClientID(t *testing.T) {
+	hasClient := mock.MatchedBy(func(ctx context.Context) bool {
+		return core.ClientIDFromContext(ctx) == "team-a"
+	})
+
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(hasClient, []string{"code"}, core.Filter{}, mock.Anything)
+	handler.EXPECT().GetCodeStyle(hasClient, []string{"code"}, core.Filter{}).Return(nil, nil)
+	handler.EXPECT().GetByNames(hasClient, []string{"rule1"}).Return(nil, nil, nil)
+	handler.EXPECT().RecordByNames(hasClient, []string{"rule1"}, mock.Anything)
+	handler.EXPECT().Stats(hasClient).Return(core.RepoStats{}, nil)
+	handler.EXPECT().Recommend(hasClient, "fmt.Errorf", []string{core.AllCategories}, 0).Return(nil, nil)
+
+	svc := New(&Config{}, handler, ServerInfo{})
+	ctx := context.Background()
+
+	_, err := svc.handleCodeStyle(ctx, CodeStyleArgs{Categories: "code", FilterArgs: FilterArgs{Client: "team-a"}})
+	require.NoError(t, err)
+
+	_, err = svc.handleGetRules(ctx, GetRulesArgs{Names: "rule1", Client: "team-a"})
+	require.NoError(t, err)
+
+	_, err = svc.handleStats(ctx, StatsArgs{Client: "team-a"})
+	require.NoError(t, err)
+
+	_, err = svc.handleRecommend(ctx, RecommendArgs{Code: "fmt.Errorf", Client: "team-a"})
+	require.NoError(t, err)
+}
+
+func TestService_handleCodeStyle_AllowedCategories(t *testing.T) {
+	tests := []struct {
+		wantErr        error
+		name           string
+		categories     string
+		wantCategories []string
+		allowed        []string
+	}{
+		{
+			name:           "no allowlist",
+			categories:     "template",
+			wantCategories: []string{"template"},
+		},
+		{
+			name:           "allowed category",
+			allowed:        []string{"code", "testing"},
+			categories:     "testing",
+			wantCategories: []string{"testing"},
+		},
+		{
+			name:           "wildcard expands to allowed categories",
+			allowed:        []string{"testing", "code"},
+			categories:     "*,code",
+			wantCategories: []string{"code", "testing"},
+		},
+		{
+			name:           "descendant of allowed category",
+			allowed:        []string{"code"},
+			categories:     "code/concurrency",
+			wantCategories: []string{"code/concurrency"},
+		},
+		{
+			name:           "parent narrowed to allowed descendants",
+			allowed:        []string{"code/errors", "code/concurrency", "testing"},
+			categories:     "code",
+			wantCategories: []string{"code/concurrency", "code/errors"},
+		},
+		{
+			name:       "rejected category",
+			allowed:    []string{"code"},
+			categories: "code,template",
+			wantErr:    ErrCategoryNotAllowed,
+		},
+		{
+			name:       "rejected sibling of allowed descendant",
+			allowed:    []string{"template/cli"},
+			categories: "template/service",
+			wantErr:    ErrCategoryNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewMockToolHandler(t)
+			if tt.wantErr == nil {
+				handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+				handler.EXPECT().GetCodeStyle(mock.Anything, tt.wantCategories, core.Filter{}).Return([]core.Rule{}, nil)
+			}
+
+			svc := New(&Config{AllowedCategories: tt.allowed}, handler, ServerInfo{})
+
+			resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: tt.categories})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorContains(t, err, "template")
+				assert.Nil(t, resp)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_handleGetRules_AllowedCategories(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().GetByNames(mock.Anything, []string{"hidden", "shared", "visible"}).Return([]core.Rule{
+		{Name: "hidden", Category: "template", Description: "Hidden rule"},
+		{Name: "shared", Category: "template", Description: "Shared template rule"},
+		{Name: "shared", Category: "code", Description: "Shared code rule"},
+		{Name: "visible", Category: "code", Description: "Visible rule"},
+	}, nil, nil)
+	// Rules outside the allowed categories are not recorded as returned
+	handler.EXPECT().RecordByNames(mock.Anything, []string{"hidden", "shared", "visible"}, []core.Rule{
+		{Name: "shared", Category: "code", Description: "Shared code rule"},
+		{Name: "visible", Category: "code", Description: "Visible rule"},
+	})
+
+	svc := New(&Config{AllowedCategories: []string{"code"}}, handler, ServerInfo{})
+
+	resp, err := svc.handleGetRules(context.Background(), GetRulesArgs{Names: "hidden,shared,visible"})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Content, 2)
+
+	text := resp.Content[0].TextContent.Text
+	assert.Contains(t, text, "Shared code rule")
+	assert.Contains(t, text, "Visible rule")
+	assert.NotContains(t, text, "Hidden rule")
+	assert.NotContains(t, text, "Shared template rule")
+	assert.Equal(t, "Missing rules: hidden", resp.Content[1].TextContent.Text)
+}
+
+func TestService_handleRuleHits_AllowedCategories(t *testing.T) {
+	resource, err := static.New(&static.Config{
+		{Name: "hidden", Category: "template", Description: "Hidden rule"},
+		{Name: "shared", Category: "template", Description: "Shared template rule"},
+		{Name: "shared", Category: "code", Description: "Shared code rule"},
+		{Name: "visible", Category: "code/concurrency", Description: "Visible rule"},
+	}, &static.Options{})
+	require.NoError(t, err)
+
+	svc := New(&Config{AllowedCategories: []string{"code"}}, core.New(resource), ServerInfo{})
+	ctx := context.Background()
+
+	_, err = svc.handleGetRules(ctx, GetRulesArgs{Names: "hidden,shared,visible"})
+	require.NoError(t, err)
+
+	_, err = svc.handleCodeStyle(ctx, CodeStyleArgs{Categories: "*"})
+	require.NoError(t, err)
+
+	resp, err := svc.handleRuleHits(ctx, RuleHitsArgs{})
+	require.NoError(t, err)
+
+	// Hits only count the rules returned from the allowed categories
+	assert.JSONEq(t, `{"shared":2,"visible":2}`, resp.Content[0].TextContent.Text)
+}
+
+func TestService_handleCodeStyle_DefaultCategories(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{core.AllCategories}, core.Filter{}).Return([]core.Rule{
+		{
+			Name:        "test_rule",
+			Category:    "code",
+			Description: "Test rule",
+		},
+	}, nil)
+
+	svc := New(&Config{DefaultCategories: []string{core.AllCategories}}, handler, ServerInfo{})
+
+	resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Content, 2)
+	assert.Contains(t, resp.Content[0].TextContent.Text, "Test rule")
+}
+
+func TestCapSections(t *testing.T) {
+	a, b, c := strings.Repeat("a", 40), strings.Repeat("b", 40), strings.Repeat("c", 200)
+	sections := []string{a, b, c}
+
+	tests := []struct {
+		wantErr      error
+		name         string
+		want         string
+		maxBytes     int
+		wantIncluded int
+	}{
+		{
+			name:         "no cap",
+			maxBytes:     0,
+			want:         a + "\n" + b + "\n" + c,
+			wantIncluded: 3,
+		},
+		{
+			name:         "within cap",
+			maxBytes:     282,
+			want:         a + "\n" + b + "\n" + c,
+			wantIncluded: 3,
+		},
+		{
+			name:         "truncated on section boundary",
+			maxBytes:     150,
+			want:         a + "\n" + b + "\n[Response truncated: 1 more rules omitted to stay within 150 bytes]",
+			wantIncluded: 2,
+		},
+		{
+			name:         "notice counts towards the cap",
+			maxBytes:     148,
+			want:         a + "\n[Response truncated: 2 more rules omitted to stay within 148 bytes]",
+			wantIncluded: 1,
+		},
+		{
+			name:     "single section exceeds cap",
+			maxBytes: 100,
+			wantErr:  ErrResponseTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, included, err := capSections(sections, "\n", tt.maxBytes)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, content)
+			assert.Equal(t, tt.wantIncluded, included)
+
+			if tt.maxBytes > 0 {
+				assert.LessOrEqual(t, len(content), tt.maxBytes)
+			}
+		})
+	}
+}
+
+func TestService_handleCodeStyle_MaxResponseBytes(t *testing.T) {
+	rules := []core.Rule{
+		{Name: "rule1", Category: "code", Description: strings.Repeat("a", 50)},
+		{Name: "rule2", Category: "code", Description: strings.Repeat("b", 50)},
+		{Name: "rule3", Category: "code", Description: strings.Repeat("c", 500)},
+	}
+
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{}).Return(rules, nil)
+
+	svc := New(&Config{MaxResponseBytes: 300}, handler, ServerInfo{})
+
+	resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code"})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Content, 2)
+
+	text := resp.Content[0].TextContent.Text
+	assert.LessOrEqual(t, len(text), 300)
+	assert.Contains(t, text, strings.Repeat("b", 50))
+	assert.NotContains(t, text, "ccc")
+	assert.Contains(t, text, "[Response truncated: 1 more rules omitted")
+	assert.JSONEq(t, `{"categories":["code"],"matched":2,"truncated":true}`, resp.Content[1].TextContent.Text)
+}
+
+func TestService_formatResponse_Preamble(t *testing.T) {
+	const preamble = "Apply these rules strictly; prefer higher priority on conflict."
+
+	rules := []core.Rule{{Name: "rule1", Category: "code", Description: "Rule one"}}
+
+	tests := []struct {
+		name   string
+		want   string
+		rules  []core.Rule
+		config Config
+		opts   renderOptions
+	}{
+		{
+			name:   "no preamble",
+			config: Config{},
+			rules:  rules,
+			want:   "Name: rule1\nDescription: Rule one\n---",
+		},
+		{
+			name:   "preamble",
+			config: Config{ResponsePreamble: preamble},
+			rules:  rules,
+			want:   preamble + "\n\nName: rule1\nDescription: Rule one\n---",
+		},
+		{
+			name:   "no rules",
+			config: Config{ResponsePreamble: preamble},
+			want:   "",
+		},
+		{
+			name:   "names only",
+			config: Config{ResponsePreamble: preamble},
+			rules:  rules,
+			opts:   renderOptions{namesOnly: true},
+			want:   "rule1 (code)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(&tt.config, NewMockToolHandler(t), ServerInfo{})
+
+			got, _, err := svc.formatResponse(tt.rules, FormatText, tt.opts)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_handleGetRules_Preamble(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordByNames(mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetByNames(mock.Anything, []string{"rule1"}).
+		Return([]core.Rule{{Name: "rule1", Category: "code", Description: "Rule one"}}, nil, nil)
+
+	svc := New(&Config{ResponsePreamble: "Follow these rules."}, handler, ServerInfo{})
+
+	resp, err := svc.handleGetRules(context.Background(), GetRulesArgs{Names: "rule1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Follow these rules.\n\nDescription: Rule one\n---", resp.Content[0].TextContent.Text)
+}
+
+func TestService_handleCodeStyle_SingleRuleTooLarge(t *testing.T) {
+	handler := NewMockToolHandler(t)
+	handler.EXPECT().RecordCodeStyle(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	handler.EXPECT().GetCodeStyle(mock.Anything, []string{"code"}, core.Filter{}).Return([]core.Rule{
+		{Name: "rule1", Category: "code", Description: strings.Repeat("a", 500)},
+	}, nil)
+
+	svc := New(&Config{MaxResponseBytes: 100}, handler, ServerInfo{})
+
+	resp, err := svc.handleCodeStyle(context.Background(), CodeStyleArgs{Categories: "code"})
+
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.Nil(t, resp)
+}
+
+func TestFormatRules(t *testing.T) {
+	rules := []core.Rule{
+		{
+			Name:        "test_rule",
+			Category:    "testing",
+			Description: "Test rule",
+			Examples: []core.Example{
+				{
+					Description: "Example",
+					Code:        "test code\n",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		wantErr    error
+		name       string
+		format     string
+		contains   []string
+		excludes   []string
+		noExamples bool
+	}{
+		{
+			name:     "default text",
+			format:   "",
+			contains: []string{"Description: Test rule", "Example (Example):", "---"},
+		},
+		{
+			name:     "explicit text",
+			format:   FormatText,
+			contains: []string{"Description: Test rule", "---"},
+		},
+		{
+			name:     "markdown",
+			format:   FormatMarkdown,
+			contains: []string{"## test_rule\n", "\nTest rule\n", "### Example\n", "```\ntest code\n```"},
+		},
+		{
+			name:       "text without examples",
+			format:     FormatText,
+			noExamples: true,
+			contains:   []string{"Name: test_rule\nDescription: Test rule", "---"},
+			excludes:   []string{"test code"},
+		},
+		{
+			name:       "markdown without examples",
+			format:     FormatMarkdown,
+			noExamples: true,
+			contains:   []string{"## test_rule\n", "\nTest rule\n"},
+			excludes:   []string{"test code", "### Example"},
+		},
+		{
+			name:    "unsupported format",
+			format:  "html",
+			wantErr: ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := formatRules(rules, tt.format, renderOptions{includeExamples: !tt.noExamples})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			for _, want := range tt.contains {
+				assert.Contains(t, content, want)
+			}
+
+			for _, unwanted := range tt.excludes {
+				assert.NotContains(t, content, unwanted)
+			}
+		})
+	}
 }
@@ -24,9 +24,133 @@ func (_m *MockToolHandler) EXPECT() *MockToolHandler_Expecter {
 	return &MockToolHandler_Expecter{mock: &_m.Mock}
 }
 
-// GetCodeStyle provides a mock function with given fields: ctx, categories
-func (_m *MockToolHandler) GetCodeStyle(ctx context.Context, categories []string) ([]core.Rule, error) {
-	ret := _m.Called(ctx, categories)
+// Fingerprint provides a mock function with given fields: ctx
+func (_m *MockToolHandler) Fingerprint(ctx context.Context) (string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fingerprint")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockToolHandler_Fingerprint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fingerprint'
+type MockToolHandler_Fingerprint_Call struct {
+	*mock.Call
+}
+
+// Fingerprint is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockToolHandler_Expecter) Fingerprint(ctx interface{}) *MockToolHandler_Fingerprint_Call {
+	return &MockToolHandler_Fingerprint_Call{Call: _e.mock.On("Fingerprint", ctx)}
+}
+
+func (_c *MockToolHandler_Fingerprint_Call) Run(run func(ctx context.Context)) *MockToolHandler_Fingerprint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockToolHandler_Fingerprint_Call) Return(_a0 string, _a1 error) *MockToolHandler_Fingerprint_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockToolHandler_Fingerprint_Call) RunAndReturn(run func(context.Context) (string, error)) *MockToolHandler_Fingerprint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByNames provides a mock function with given fields: ctx, names
+func (_m *MockToolHandler) GetByNames(ctx context.Context, names []string) ([]core.Rule, []string, error) {
+	ret := _m.Called(ctx, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByNames")
+	}
+
+	var r0 []core.Rule
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]core.Rule, []string, error)); ok {
+		return rf(ctx, names)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []core.Rule); ok {
+		r0 = rf(ctx, names)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.Rule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) []string); ok {
+		r1 = rf(ctx, names)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []string) error); ok {
+		r2 = rf(ctx, names)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockToolHandler_GetByNames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByNames'
+type MockToolHandler_GetByNames_Call struct {
+	*mock.Call
+}
+
+// GetByNames is a helper method to define mock.On call
+//   - ctx context.Context
+//   - names []string
+func (_e *MockToolHandler_Expecter) GetByNames(ctx interface{}, names interface{}) *MockToolHandler_GetByNames_Call {
+	return &MockToolHandler_GetByNames_Call{Call: _e.mock.On("GetByNames", ctx, names)}
+}
+
+func (_c *MockToolHandler_GetByNames_Call) Run(run func(ctx context.Context, names []string)) *MockToolHandler_GetByNames_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockToolHandler_GetByNames_Call) Return(rules []core.Rule, missing []string, err error) *MockToolHandler_GetByNames_Call {
+	_c.Call.Return(rules, missing, err)
+	return _c
+}
+
+func (_c *MockToolHandler_GetByNames_Call) RunAndReturn(run func(context.Context, []string) ([]core.Rule, []string, error)) *MockToolHandler_GetByNames_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCodeStyle provides a mock function with given fields: ctx, categories, filter
+func (_m *MockToolHandler) GetCodeStyle(ctx context.Context, categories []string, filter core.Filter) ([]core.Rule, error) {
+	ret := _m.Called(ctx, categories, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCodeStyle")
@@ -34,19 +158,19 @@ func (_m *MockToolHandler) GetCodeStyle(ctx context.Context, categories []string
 
 	var r0 []core.Rule
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]core.Rule, error)); ok {
-		return rf(ctx, categories)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, core.Filter) ([]core.Rule, error)); ok {
+		return rf(ctx, categories, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []string) []core.Rule); ok {
-		r0 = rf(ctx, categories)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, core.Filter) []core.Rule); ok {
+		r0 = rf(ctx, categories, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]core.Rule)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
-		r1 = rf(ctx, categories)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, core.Filter) error); ok {
+		r1 = rf(ctx, categories, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -62,13 +186,14 @@ type MockToolHandler_GetCodeStyle_Call struct {
 // GetCodeStyle is a helper method to define mock.On call
 //   - ctx context.Context
 //   - categories []string
-func (_e *MockToolHandler_Expecter) GetCodeStyle(ctx interface{}, categories interface{}) *MockToolHandler_GetCodeStyle_Call {
-	return &MockToolHandler_GetCodeStyle_Call{Call: _e.mock.On("GetCodeStyle", ctx, categories)}
+//   - filter core.Filter
+func (_e *MockToolHandler_Expecter) GetCodeStyle(ctx interface{}, categories interface{}, filter interface{}) *MockToolHandler_GetCodeStyle_Call {
+	return &MockToolHandler_GetCodeStyle_Call{Call: _e.mock.On("GetCodeStyle", ctx, categories, filter)}
 }
 
-func (_c *MockToolHandler_GetCodeStyle_Call) Run(run func(ctx context.Context, categories []string)) *MockToolHandler_GetCodeStyle_Call {
+func (_c *MockToolHandler_GetCodeStyle_Call) Run(run func(ctx context.Context, categories []string, filter core.Filter)) *MockToolHandler_GetCodeStyle_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].([]string))
+		run(args[0].(context.Context), args[1].([]string), args[2].(core.Filter))
 	})
 	return _c
 }
@@ -78,7 +203,242 @@ func (_c *MockToolHandler_GetCodeStyle_Call) Return(_a0 []core.Rule, _a1 error)
 	return _c
 }
 
-func (_c *MockToolHandler_GetCodeStyle_Call) RunAndReturn(run func(context.Context, []string) ([]core.Rule, error)) *MockToolHandler_GetCodeStyle_Call {
+func (_c *MockToolHandler_GetCodeStyle_Call) RunAndReturn(run func(context.Context, []string, core.Filter) ([]core.Rule, error)) *MockToolHandler_GetCodeStyle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Recommend provides a mock function with given fields: ctx, code, categories, limit
+func (_m *MockToolHandler) Recommend(ctx context.Context, code string, categories []string, limit int) ([]core.Rule, error) {
+	ret := _m.Called(ctx, code, categories, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Recommend")
+	}
+
+	var r0 []core.Rule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, int) ([]core.Rule, error)); ok {
+		return rf(ctx, code, categories, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, int) []core.Rule); ok {
+		r0 = rf(ctx, code, categories, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.Rule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, int) error); ok {
+		r1 = rf(ctx, code, categories, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockToolHandler_Recommend_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Recommend'
+type MockToolHandler_Recommend_Call struct {
+	*mock.Call
+}
+
+// Recommend is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+//   - categories []string
+//   - limit int
+func (_e *MockToolHandler_Expecter) Recommend(ctx interface{}, code interface{}, categories interface{}, limit interface{}) *MockToolHandler_Recommend_Call {
+	return &MockToolHandler_Recommend_Call{Call: _e.mock.On("Recommend", ctx, code, categories, limit)}
+}
+
+func (_c *MockToolHandler_Recommend_Call) Run(run func(ctx context.Context, code string, categories []string, limit int)) *MockToolHandler_Recommend_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockToolHandler_Recommend_Call) Return(_a0 []core.Rule, _a1 error) *MockToolHandler_Recommend_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockToolHandler_Recommend_Call) RunAndReturn(run func(context.Context, string, []string, int) ([]core.Rule, error)) *MockToolHandler_Recommend_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordByNames provides a mock function with given fields: ctx, names, rules
+func (_m *MockToolHandler) RecordByNames(ctx context.Context, names []string, rules []core.Rule) {
+	_m.Called(ctx, names, rules)
+}
+
+// MockToolHandler_RecordByNames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordByNames'
+type MockToolHandler_RecordByNames_Call struct {
+	*mock.Call
+}
+
+// RecordByNames is a helper method to define mock.On call
+//   - ctx context.Context
+//   - names []string
+//   - rules []core.Rule
+func (_e *MockToolHandler_Expecter) RecordByNames(ctx interface{}, names interface{}, rules interface{}) *MockToolHandler_RecordByNames_Call {
+	return &MockToolHandler_RecordByNames_Call{Call: _e.mock.On("RecordByNames", ctx, names, rules)}
+}
+
+func (_c *MockToolHandler_RecordByNames_Call) Run(run func(ctx context.Context, names []string, rules []core.Rule)) *MockToolHandler_RecordByNames_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].([]core.Rule))
+	})
+	return _c
+}
+
+func (_c *MockToolHandler_RecordByNames_Call) Return() *MockToolHandler_RecordByNames_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockToolHandler_RecordByNames_Call) RunAndReturn(run func(context.Context, []string, []core.Rule)) *MockToolHandler_RecordByNames_Call {
+	_c.Run(run)
+	return _c
+}
+
+// RecordCodeStyle provides a mock function with given fields: ctx, categories, filter, rules
+func (_m *MockToolHandler) RecordCodeStyle(ctx context.Context, categories []string, filter core.Filter, rules []core.Rule) {
+	_m.Called(ctx, categories, filter, rules)
+}
+
+// MockToolHandler_RecordCodeStyle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordCodeStyle'
+type MockToolHandler_RecordCodeStyle_Call struct {
+	*mock.Call
+}
+
+// RecordCodeStyle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - categories []string
+//   - filter core.Filter
+//   - rules []core.Rule
+func (_e *MockToolHandler_Expecter) RecordCodeStyle(ctx interface{}, categories interface{}, filter interface{}, rules interface{}) *MockToolHandler_RecordCodeStyle_Call {
+	return &MockToolHandler_RecordCodeStyle_Call{Call: _e.mock.On("RecordCodeStyle", ctx, categories, filter, rules)}
+}
+
+func (_c *MockToolHandler_RecordCodeStyle_Call) Run(run func(ctx context.Context, categories []string, filter core.Filter, rules []core.Rule)) *MockToolHandler_RecordCodeStyle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].(core.Filter), args[3].([]core.Rule))
+	})
+	return _c
+}
+
+func (_c *MockToolHandler_RecordCodeStyle_Call) Return() *MockToolHandler_RecordCodeStyle_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockToolHandler_RecordCodeStyle_Call) RunAndReturn(run func(context.Context, []string, core.Filter, []core.Rule)) *MockToolHandler_RecordCodeStyle_Call {
+	_c.Run(run)
+	return _c
+}
+
+// RuleHits provides a mock function with no fields
+func (_m *MockToolHandler) RuleHits() map[string]int {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RuleHits")
+	}
+
+	var r0 map[string]int
+	if rf, ok := ret.Get(0).(func() map[string]int); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	return r0
+}
+
+// MockToolHandler_RuleHits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RuleHits'
+type MockToolHandler_RuleHits_Call struct {
+	*mock.Call
+}
+
+// RuleHits is a helper method to define mock.On call
+func (_e *MockToolHandler_Expecter) RuleHits() *MockToolHandler_RuleHits_Call {
+	return &MockToolHandler_RuleHits_Call{Call: _e.mock.On("RuleHits")}
+}
+
+func (_c *MockToolHandler_RuleHits_Call) Run(run func()) *MockToolHandler_RuleHits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockToolHandler_RuleHits_Call) Return(_a0 map[string]int) *MockToolHandler_RuleHits_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockToolHandler_RuleHits_Call) RunAndReturn(run func() map[string]int) *MockToolHandler_RuleHits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function with given fields: ctx
+func (_m *MockToolHandler) Stats(ctx context.Context) (core.RepoStats, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 core.RepoStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (core.RepoStats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) core.RepoStats); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(core.RepoStats)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockToolHandler_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type MockToolHandler_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockToolHandler_Expecter) Stats(ctx interface{}) *MockToolHandler_Stats_Call {
+	return &MockToolHandler_Stats_Call{Call: _e.mock.On("Stats", ctx)}
+}
+
+func (_c *MockToolHandler_Stats_Call) Run(run func(ctx context.Context)) *MockToolHandler_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockToolHandler_Stats_Call) Return(_a0 core.RepoStats, _a1 error) *MockToolHandler_Stats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockToolHandler_Stats_Call) RunAndReturn(run func(context.Context) (core.RepoStats, error)) *MockToolHandler_Stats_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/metoro-io/mcp-golang/transport"
+)
+
+// DefaultMaxRequestBytes is the size limit of a request line when MaxRequestBytes is not set.
+const DefaultMaxRequestBytes = 16 << 20
+
+// invalidRequestCode is the JSON-RPC error code for requests that are not valid request objects.
+const invalidRequestCode = -32600
+
+// maxReadErrors is the number of consecutive read errors after which the input is
+// considered broken and the transport stops reading.
+const maxReadErrors = 3
+
+// errRequestTooLarge is reported for request lines longer than the maxRequestBytes of the transport.
+var errRequestTooLarge = errors.New("request too large")
+
+// errUnknownMessage is reported for request lines that are not JSON-RPC messages.
+var errUnknownMessage = errors.New("unrecognized JSON-RPC message")
+
+// stdioTransport is an MCP transport exchanging newline-delimited JSON-RPC messages
+// over a reader and a writer, usually stdin and stdout. Unlike the stdio transport
+// of the mcp library, it reads whole lines up to maxRequestBytes, so requests larger
+// than the read buffer are not split or corrupted.
+type stdioTransport struct {
+	in              io.Reader
+	out             io.Writer
+	onClose         func()
+	onError         func(error)
+	onMessage       func(ctx context.Context, message *transport.BaseJsonRpcMessage)
+	maxRequestBytes int
+	mu              sync.Mutex
+	started         bool
+}
+
+// newStdioTransport creates a transport reading requests of up to maxRequestBytes from in
+// and writing responses to out. If maxRequestBytes is not positive, DefaultMaxRequestBytes is used.
+func newStdioTransport(in io.Reader, out io.Writer, maxRequestBytes int) *stdioTransport {
+	if maxRequestBytes <= 0 {
+		maxRequestBytes = DefaultMaxRequestBytes
+	}
+
+	return &stdioTransport{
+		in:              in,
+		out:             out,
+		maxRequestBytes: maxRequestBytes,
+	}
+}
+
+// Start begins reading messages in the background until the input ends or ctx is cancelled.
+func (t *stdioTransport) Start(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.started {
+		return errors.New("stdio transport already started")
+	}
+
+	t.started = true
+
+	go t.readLoop(ctx)
+
+	return nil
+}
+
+// Close stops handling incoming messages and invokes the close handler.
+func (t *stdioTransport) Close() error {
+	t.mu.Lock()
+	t.started = false
+	handler := t.onClose
+	t.mu.Unlock()
+
+	if handler != nil {
+		handler()
+	}
+
+	return nil
+}
+
+// Send writes message as a single line.
+func (t *stdioTransport) Send(_ context.Context, message *transport.BaseJsonRpcMessage) error {
+	return t.write(message)
+}
+
+// write marshals message to JSON and writes it as a single line.
+func (t *stdioTransport) write(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, err = t.out.Write(append(data, '\n'))
+
+	return err
+}
+
+// SetCloseHandler sets the handler invoked when the transport is closed.
+func (t *stdioTransport) SetCloseHandler(handler func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onClose = handler
+}
+
+// SetErrorHandler sets the handler invoked for read and parse errors.
+func (t *stdioTransport) SetErrorHandler(handler func(error)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onError = handler
+}
+
+// SetMessageHandler sets the handler invoked for each incoming message.
+func (t *stdioTransport) SetMessageHandler(handler func(ctx context.Context, message *transport.BaseJsonRpcMessage)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onMessage = handler
+}
+
+// readLoop reads the input line by line and dispatches each message until the input
+// ends. Lines that are not valid messages are reported to the error handler and skipped.
+// Lines longer than maxRequestBytes are discarded up to their end, reported to the error
+// handler and answered with an invalid request error, so the client is not left waiting
+// and the following requests are still served.
+// Read errors are reported to the error handler and reading resumes with the next line;
+// the loop only stops on a closed input or after maxReadErrors consecutive read errors.
+func (t *stdioTransport) readLoop(ctx context.Context) {
+	reader := bufio.NewReaderSize(t.in, min(bufio.MaxScanTokenSize, t.maxRequestBytes))
+	readErrors := 0
+
+	for {
+		line, err := t.readLine(reader)
+		if ctx.Err() != nil || !t.isStarted() {
+			return
+		}
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return
+		case errors.Is(err, errRequestTooLarge):
+			t.handleTooLarge()
+			continue
+		case err != nil:
+			t.handleError(fmt.Errorf("read error: %w", err))
+
+			readErrors++
+			if readErrors >= maxReadErrors || errors.Is(err, os.ErrClosed) || errors.Is(err, io.ErrClosedPipe) {
+				return
+			}
+
+			continue
+		}
+
+		readErrors = 0
+
+		if len(line) == 0 {
+			continue
+		}
+
+		message, err := parseMessage(line)
+		if err != nil {
+			t.handleError(err)
+			continue
+		}
+
+		t.handleMessage(message)
+	}
+}
+
+// readLine returns the next line of r without its line ending.
+// Returns errRequestTooLarge if the line is longer than maxRequestBytes, after discarding
+// the rest of it, or io.EOF once the input ends.
+func (t *stdioTransport) readLine(r *bufio.Reader) ([]byte, error) {
+	var (
+		line    []byte
+		tooLong bool
+	)
+
+	for {
+		chunk, err := r.ReadSlice('\n')
+
+		// The line ending may follow the longest accepted line
+		if !tooLong && len(line)+len(chunk) > t.maxRequestBytes+len("\r\n") {
+			line, tooLong = nil, true
+		}
+
+		if !tooLong {
+			line = append(line, chunk...)
+		}
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+
+		if err != nil && (!errors.Is(err, io.EOF) || (len(line) == 0 && !tooLong)) {
+			return nil, err
+		}
+
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+		if tooLong || len(line) > t.maxRequestBytes {
+			return nil, errRequestTooLarge
+		}
+
+		return line, nil
+	}
+}
+
+// handleTooLarge reports a request line longer than maxRequestBytes to the error handler
+// and answers it with an invalid request error. Its id is null, as the id of a discarded
+// request is unknown.
+func (t *stdioTransport) handleTooLarge() {
+	err := fmt.Errorf("%w: exceeds %d bytes", errRequestTooLarge, t.maxRequestBytes)
+
+	t.handleError(err)
+
+	response := struct {
+		ID      *transport.RequestId            `json:"id"`
+		Jsonrpc string                          `json:"jsonrpc"`
+		Error   transport.BaseJSONRPCErrorInner `json:"error"`
+	}{
+		Jsonrpc: "2.0",
+		Error:   transport.BaseJSONRPCErrorInner{Code: invalidRequestCode, Message: err.Error()},
+	}
+
+	if sendErr := t.write(response); sendErr != nil {
+		t.handleError(fmt.Errorf("failed to send error response: %w", sendErr))
+	}
+}
+
+// isStarted reports whether the transport was started and not closed since.
+func (t *stdioTransport) isStarted() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.started
+}
+
+// handleError passes err to the error handler, if any.
+func (t *stdioTransport) handleError(err error) {
+	t.mu.Lock()
+	handler := t.onError
+	t.mu.Unlock()
+
+	if handler != nil {
+		handler(err)
+	}
+}
+
+// handleMessage passes message to the message handler, if any.
+func (t *stdioTransport) handleMessage(message *transport.BaseJsonRpcMessage) {
+	t.mu.Lock()
+	handler := t.onMessage
+	t.mu.Unlock()
+
+	if handler != nil {
+		handler(context.Background(), message)
+	}
+}
+
+// parseMessage decodes a JSON-RPC request, notification, response or error, tried in
+// that order as in the stdio transport of the mcp library.
+func parseMessage(line []byte) (*transport.BaseJsonRpcMessage, error) {
+	var request transport.BaseJSONRPCRequest
+	if err := json.Unmarshal(line, &request); err == nil {
+		return transport.NewBaseMessageRequest(&request), nil
+	}
+
+	var notification transport.BaseJSONRPCNotification
+	if err := json.Unmarshal(line, &notification); err == nil {
+		return transport.NewBaseMessageNotification(&notification), nil
+	}
+
+	var response transport.BaseJSONRPCResponse
+	if err := json.Unmarshal(line, &response); err == nil {
+		return transport.NewBaseMessageResponse(&response), nil
+	}
+
+	var errorResponse transport.BaseJSONRPCError
+	if err := json.Unmarshal(line, &errorResponse); err == nil {
+		return transport.NewBaseMessageError(&errorResponse), nil
+	}
+
+	return nil, errUnknownMessage
+}
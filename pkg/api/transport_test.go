@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/metoro-io/mcp-golang/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectMessages reads the whole input of tr and returns the messages and errors it reports.
+func collectMessages(tr *stdioTransport) ([]*transport.BaseJsonRpcMessage, []error) {
+	var (
+		messages []*transport.BaseJsonRpcMessage
+		errs     []error
+	)
+
+	tr.SetMessageHandler(func(_ context.Context, message *transport.BaseJsonRpcMessage) {
+		messages = append(messages, message)
+	})
+	tr.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	// Run the loop Start would spawn synchronously, so that it ends with the input
+	tr.started = true
+	tr.readLoop(context.Background())
+
+	return messages, errs
+}
+
+func TestStdioTransport_LargeRequest(t *testing.T) {
+	params := `{"code":"` + strings.Repeat("x", 4*bufio.MaxScanTokenSize) + `"}`
+	input := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":` + params + "}\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"
+
+	messages, errs := collectMessages(newStdioTransport(strings.NewReader(input), &bytes.Buffer{}, 0))
+
+	assert.Empty(t, errs)
+	require.Len(t, messages, 2)
+	require.NotNil(t, messages[0].JsonRpcRequest)
+	assert.JSONEq(t, params, string(messages[0].JsonRpcRequest.Params))
+	require.NotNil(t, messages[1].JsonRpcNotification)
+	assert.Equal(t, "notifications/initialized", messages[1].JsonRpcNotification.Method)
+}
+
+func TestStdioTransport_RequestTooLarge(t *testing.T) {
+	input := `{"jsonrpc":"2.0","id":1,"method":"ping","params":{"pad":"` + strings.Repeat("x", 1024) + `"}}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}` + "\n" +
+		`{"jsonrpc":"2.0","id":3,"method":"ping","params":{"pad":"` + strings.Repeat("x", 1024) + `"}}`
+
+	var out bytes.Buffer
+
+	messages, errs := collectMessages(newStdioTransport(strings.NewReader(input), &out, 512))
+
+	// The requests following an oversized one are still read
+	require.Len(t, messages, 1)
+	require.NotNil(t, messages[0].JsonRpcRequest)
+	assert.Equal(t, transport.RequestId(2), messages[0].JsonRpcRequest.Id)
+
+	require.Len(t, errs, 2)
+	assert.ErrorIs(t, errs[0], errRequestTooLarge)
+	assert.Contains(t, errs[0].Error(), "512 bytes")
+	assert.ErrorIs(t, errs[1], errRequestTooLarge)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"request too large: exceeds 512 bytes"}}`, lines[0])
+}
+
+func TestStdioTransport_readLine(t *testing.T) {
+	tr := newStdioTransport(nil, nil, 8)
+	reader := bufio.NewReaderSize(strings.NewReader("12345678\r\n123456789\n\nlast"), 16)
+
+	line, err := tr.readLine(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678", string(line), "a line of maxRequestBytes is accepted")
+
+	_, err = tr.readLine(reader)
+	require.ErrorIs(t, err, errRequestTooLarge)
+
+	line, err = tr.readLine(reader)
+	require.NoError(t, err)
+	assert.Empty(t, line)
+
+	line, err = tr.readLine(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "last", string(line), "the last line needs no line ending")
+
+	_, err = tr.readLine(reader)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestStdioTransport_SkipsInvalidLines(t *testing.T) {
+	input := "not json\n\n" + `{"jsonrpc":"2.0","id":2,"method":"ping"}` + "\n"
+
+	messages, errs := collectMessages(newStdioTransport(strings.NewReader(input), &bytes.Buffer{}, 0))
+
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], errUnknownMessage)
+	require.Len(t, messages, 1)
+	require.NotNil(t, messages[0].JsonRpcRequest)
+	assert.Equal(t, "ping", messages[0].JsonRpcRequest.Method)
+}
+
+// readStep is the result of a single Read call of scriptedReader.
+type readStep struct {
+	err  error
+	data string
+}
+
+// scriptedReader returns its steps from successive Read calls, then io.EOF.
+type scriptedReader struct {
+	steps []readStep
+}
+
+func (r *scriptedReader) Read(p []byte) (int, error) {
+	if len(r.steps) == 0 {
+		return 0, io.EOF
+	}
+
+	step := r.steps[0]
+	r.steps = r.steps[1:]
+
+	return copy(p, step.data), step.err
+}
+
+func TestStdioTransport_RecoversFromReadErrors(t *testing.T) {
+	errHiccup := errors.New("read hiccup")
+
+	in := &scriptedReader{steps: []readStep{
+		{data: `{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n" + `{"jsonrpc":"2.0",`},
+		{err: errHiccup},
+		{data: `"id":2}` + "\n" + `{"jsonrpc":"2.0","id":3,"method":"ping"}` + "\n"},
+	}}
+
+	messages, errs := collectMessages(newStdioTransport(in, &bytes.Buffer{}, 0))
+
+	// The line interrupted by the error is dropped and its remainder is skipped as invalid
+	require.Len(t, errs, 2)
+	assert.ErrorIs(t, errs[0], errHiccup)
+	assert.ErrorIs(t, errs[1], errUnknownMessage)
+
+	require.Len(t, messages, 2)
+	assert.Equal(t, transport.RequestId(1), messages[0].JsonRpcRequest.Id)
+	assert.Equal(t, transport.RequestId(3), messages[1].JsonRpcRequest.Id)
+}
+
+func TestStdioTransport_StopsOnBrokenInput(t *testing.T) {
+	tests := []struct {
+		err      error
+		name     string
+		wantErrs int
+	}{
+		{name: "persistent errors", err: assert.AnError, wantErrs: maxReadErrors},
+		{name: "closed input", err: os.ErrClosed, wantErrs: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := make([]readStep, 0, maxReadErrors+1)
+			for range maxReadErrors {
+				steps = append(steps, readStep{err: tt.err})
+			}
+
+			// Never read, as the loop stops before
+			steps = append(steps, readStep{data: `{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"})
+
+			messages, errs := collectMessages(newStdioTransport(&scriptedReader{steps: steps}, &bytes.Buffer{}, 0))
+
+			assert.Empty(t, messages)
+			assert.Len(t, errs, tt.wantErrs)
+		})
+	}
+}
+
+func TestStdioTransport_Send(t *testing.T) {
+	var out bytes.Buffer
+
+	tr := newStdioTransport(strings.NewReader(""), &out, 0)
+
+	err := tr.Send(context.Background(), transport.NewBaseMessageNotification(&transport.BaseJSONRPCNotification{
+		Jsonrpc: "2.0",
+		Method:  "notifications/message",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"jsonrpc":"2.0","method":"notifications/message"}`+"\n", out.String())
+}
+
+func TestStdioTransport_StartTwice(t *testing.T) {
+	closed := false
+
+	tr := newStdioTransport(strings.NewReader(""), &bytes.Buffer{}, 0)
+	tr.SetCloseHandler(func() { closed = true })
+
+	require.NoError(t, tr.Start(context.Background()))
+	assert.Error(t, tr.Start(context.Background()))
+
+	require.NoError(t, tr.Close())
+	assert.True(t, closed)
+}
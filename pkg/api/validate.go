@@ -0,0 +1,238 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+)
+
+// Validation errors reported for tool arguments.
+var (
+	ErrCategoriesRequired     = errors.New("categories is required")
+	ErrInvalidCategory        = errors.New("invalid category")
+	ErrInvalidCategoryWeight  = errors.New("invalid category weight")
+	ErrUnsupportedFormat      = errors.New("unsupported format")
+	ErrNegativeLimit          = errors.New("per_category_limit must not be negative")
+	ErrNegativeMaxExamples    = errors.New("max_examples_per_rule must not be negative")
+	ErrNegativeMinResults     = errors.New("min_results must not be negative")
+	ErrInvalidSeverity        = errors.New("invalid min_severity")
+	ErrNamesRequired          = errors.New("names is required")
+	ErrInvalidUpdatedAt       = errors.New("updated_since must be an RFC3339 time")
+	ErrInvalidSort            = errors.New("invalid sort")
+	ErrCategoryNotAllowed     = errors.New("category is not allowed")
+	ErrCategoryNotFound       = errors.New("category has no rules")
+	ErrResponseTooLarge       = errors.New("response exceeds size limit")
+	ErrCodeRequired           = errors.New("code is required")
+	ErrNegativeRecommendLimit = errors.New("limit must not be negative")
+	ErrRuleRequired           = errors.New("rule is required")
+	ErrRuleNotFound           = errors.New("rule not found")
+)
+
+// builtinCategories lists the top-level categories always accepted by the codestyle tool,
+// in addition to those of the repository rules, see Service.knownCategories.
+// Hierarchical categories are accepted when their first level is known, e.g. "code/concurrency".
+var builtinCategories = map[string]bool{
+	core.AllCategories: true,
+	"documentation":    true,
+	"testing":          true,
+	"code":             true,
+	"template":         true,
+}
+
+// ValidationError reports every problem found in tool arguments at once,
+// so that the caller can fix them all in a single round trip.
+// Each issue wraps one of the validation sentinel errors and can be matched with errors.Is.
+type ValidationError struct {
+	Issues []error
+}
+
+// Error returns all issues joined into a single message.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		msgs[i] = issue.Error()
+	}
+
+	return "invalid arguments: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual issues.
+func (e *ValidationError) Unwrap() []error {
+	return e.Issues
+}
+
+// Validate checks the codestyle arguments and reports all problems found. Requested and
+// excluded categories must be nested in one of the known top-level categories.
+// Returns *ValidationError if any argument is invalid.
+func (a *CodeStyleArgs) Validate(known map[string]bool) error {
+	var issues []error
+
+	categories, _, weightIssues := parseCategories(a.Categories)
+	if len(categories) == 0 && len(weightIssues) == 0 {
+		issues = append(issues, ErrCategoriesRequired)
+	}
+
+	issues = append(issues, weightIssues...)
+
+	for _, cat := range append(categories, splitCategories(a.ExcludeCategories)...) {
+		if !known[categoryRoot(cat)] {
+			issues = append(issues, fmt.Errorf("%w: %s", ErrInvalidCategory, cat))
+		}
+	}
+
+	if err := validateFormat(a.Format); err != nil {
+		issues = append(issues, err)
+	}
+
+	if a.MinSeverity != "" && !core.IsValidSeverity(a.MinSeverity) {
+		issues = append(issues, fmt.Errorf("%w: %s", ErrInvalidSeverity, a.MinSeverity))
+	}
+
+	if a.PerCategoryLimit < 0 {
+		issues = append(issues, ErrNegativeLimit)
+	}
+
+	if a.MaxExamplesPerRule < 0 {
+		issues = append(issues, ErrNegativeMaxExamples)
+	}
+
+	if a.MinResults < 0 {
+		issues = append(issues, ErrNegativeMinResults)
+	}
+
+	if a.UpdatedSince != "" {
+		if _, err := time.Parse(time.RFC3339, a.UpdatedSince); err != nil {
+			issues = append(issues, fmt.Errorf("%w: %s", ErrInvalidUpdatedAt, a.UpdatedSince))
+		}
+	}
+
+	if a.Sort != "" && !core.IsValidSort(a.Sort) {
+		issues = append(issues, fmt.Errorf("%w: %s", ErrInvalidSort, a.Sort))
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+
+	return nil
+}
+
+// Validate checks the getrules arguments and reports all problems found.
+// Returns *ValidationError if any argument is invalid.
+func (a *GetRulesArgs) Validate() error {
+	var issues []error
+
+	if len(splitCategories(a.Names)) == 0 {
+		issues = append(issues, ErrNamesRequired)
+	}
+
+	if err := validateFormat(a.Format); err != nil {
+		issues = append(issues, err)
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+
+	return nil
+}
+
+// Validate checks the recommend arguments and reports all problems found.
+// Returns *ValidationError if any argument is invalid.
+func (a *RecommendArgs) Validate() error {
+	var issues []error
+
+	if strings.TrimSpace(a.Code) == "" {
+		issues = append(issues, ErrCodeRequired)
+	}
+
+	if err := validateFormat(a.Format); err != nil {
+		issues = append(issues, err)
+	}
+
+	if a.Limit < 0 {
+		issues = append(issues, ErrNegativeRecommendLimit)
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+
+	return nil
+}
+
+// Validate checks the rendertemplate arguments and reports all problems found.
+// Returns *ValidationError if any argument is invalid.
+func (a *RenderTemplateArgs) Validate() error {
+	if strings.TrimSpace(a.Rule) == "" {
+		return &ValidationError{Issues: []error{ErrRuleRequired}}
+	}
+
+	return nil
+}
+
+// validateFormat returns an error wrapping ErrUnsupportedFormat for unknown output formats.
+func validateFormat(format string) error {
+	switch format {
+	case "", FormatText, FormatMarkdown:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// parseCategories parses a comma-separated list of categories, each optionally weighted
+// as "name:weight" with a positive integer weight, e.g. "testing:2,code". It returns the
+// category names and the weights given explicitly; plain names weigh 1 and are absent
+// from weights. A category weighted several times takes its highest weight.
+// Malformed entries are left out and reported as issues wrapping ErrInvalidCategoryWeight.
+func parseCategories(raw string) (categories []string, weights map[string]int, issues []error) {
+	for _, entry := range splitCategories(raw) {
+		name, rawWeight, weighted := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+
+		if !weighted {
+			categories = append(categories, name)
+			continue
+		}
+
+		weight, err := strconv.Atoi(strings.TrimSpace(rawWeight))
+		if err != nil || weight < 1 || name == "" {
+			issues = append(issues, fmt.Errorf("%w: %s", ErrInvalidCategoryWeight, entry))
+			continue
+		}
+
+		if weights == nil {
+			weights = make(map[string]int)
+		}
+
+		categories = append(categories, name)
+		weights[name] = max(weights[name], weight)
+	}
+
+	return categories, weights, issues
+}
+
+// categoryRoot returns the top-level category of category, e.g. "code" for "code/concurrency".
+func categoryRoot(category string) string {
+	root, _, _ := strings.Cut(category, core.CategorySeparator)
+	return root
+}
+
+// splitCategories parses a comma-separated list, such as categories or rule names, trimming
+// whitespace and dropping empty entries.
+func splitCategories(raw string) []string {
+	categories := make([]string, 0)
+
+	for _, cat := range strings.Split(raw, ",") {
+		if cat = strings.TrimSpace(cat); cat != "" {
+			categories = append(categories, cat)
+		}
+	}
+
+	return categories
+}
@@ -0,0 +1,308 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeStyleArgs_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    CodeStyleArgs
+		wantErr bool
+	}{
+		{
+			name: "valid args",
+			args: CodeStyleArgs{
+				Categories: "testing",
+			},
+			wantErr: false,
+		},
+		{
+			name: "multiple categories",
+			args: CodeStyleArgs{
+				Categories: "testing,documentation",
+			},
+			wantErr: false,
+		},
+		{
+			name: "hierarchical category",
+			args: CodeStyleArgs{
+				Categories: "code/concurrency,testing",
+			},
+			wantErr: false,
+		},
+		{
+			name: "hierarchical category with invalid root",
+			args: CodeStyleArgs{
+				Categories: "invalid/concurrency",
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty categories",
+			args: CodeStyleArgs{
+				Categories: "",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid category",
+			args: CodeStyleArgs{
+				Categories: "invalid",
+			},
+			wantErr: true,
+		},
+		{
+			name: "wildcard and template",
+			args: CodeStyleArgs{
+				Categories: "*,template",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid format and limit",
+			args: CodeStyleArgs{
+				Categories: "code",
+				FilterArgs: FilterArgs{
+					Format:           FormatMarkdown,
+					PerCategoryLimit: 3,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unsupported format",
+			args: CodeStyleArgs{
+				Categories: "code",
+				FilterArgs: FilterArgs{
+					Format: "html",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid severity",
+			args: CodeStyleArgs{
+				Categories: "code",
+				FilterArgs: FilterArgs{
+					MinSeverity: "must",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid severity",
+			args: CodeStyleArgs{
+				Categories: "code",
+				FilterArgs: FilterArgs{
+					MinSeverity: "critical",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid updated_since and sort",
+			args: CodeStyleArgs{
+				Categories: "code",
+				FilterArgs: FilterArgs{
+					UpdatedSince: "2024-01-02T15:04:05Z",
+					Sort:         "updated",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid updated_since",
+			args: CodeStyleArgs{
+				Categories: "code",
+				FilterArgs: FilterArgs{
+					UpdatedSince: "2024-01-02",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid sort",
+			args: CodeStyleArgs{
+				Categories: "code",
+				FilterArgs: FilterArgs{
+					Sort: "random",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative limit",
+			args: CodeStyleArgs{
+				Categories: "code",
+				FilterArgs: FilterArgs{
+					PerCategoryLimit: -1,
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.args.Validate(builtinCategories)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCodeStyleArgs_ValidateAccumulatesIssues(t *testing.T) {
+	args := CodeStyleArgs{
+		Categories: "foo, testing, bar",
+		FilterArgs: FilterArgs{
+			Format:           "html",
+			PerCategoryLimit: -1,
+		},
+	}
+
+	err := args.Validate(builtinCategories)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Len(t, validationErr.Issues, 4)
+	assert.ErrorIs(t, err, ErrInvalidCategory)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	assert.ErrorIs(t, err, ErrNegativeLimit)
+	assert.Equal(t,
+		"invalid arguments: invalid category: foo; invalid category: bar; unsupported format: html; per_category_limit must not be negative",
+		err.Error())
+}
+
+func TestCodeStyleArgs_ValidateExcludeCategories(t *testing.T) {
+	assert.NoError(t, (&CodeStyleArgs{Categories: "*", ExcludeCategories: "documentation, code/concurrency"}).Validate(builtinCategories))
+	assert.NoError(t, (&CodeStyleArgs{Categories: "*", ExcludeCategories: " , "}).Validate(builtinCategories))
+
+	err := (&CodeStyleArgs{Categories: "*", ExcludeCategories: "docs,testing:2"}).Validate(builtinCategories)
+	assert.ErrorIs(t, err, ErrInvalidCategory)
+	assert.EqualError(t, err, "invalid arguments: invalid category: docs; invalid category: testing:2")
+}
+
+func TestCodeStyleArgs_ValidateEmptyCategories(t *testing.T) {
+	args := CodeStyleArgs{Categories: " , "}
+
+	err := args.Validate(builtinCategories)
+
+	assert.ErrorIs(t, err, ErrCategoriesRequired)
+}
+
+func TestParseCategories(t *testing.T) {
+	tests := []struct {
+		weights    map[string]int
+		name       string
+		raw        string
+		categories []string
+		issues     []string
+	}{
+		{
+			name:       "plain categories",
+			raw:        "testing, code",
+			categories: []string{"testing", "code"},
+		},
+		{
+			name:       "weighted categories",
+			raw:        "testing:2, code/concurrency : 3,documentation",
+			categories: []string{"testing", "code/concurrency", "documentation"},
+			weights:    map[string]int{"testing": 2, "code/concurrency": 3},
+		},
+		{
+			name:       "repeated category takes highest weight",
+			raw:        "testing:2,testing:5",
+			categories: []string{"testing", "testing"},
+			weights:    map[string]int{"testing": 5},
+		},
+		{
+			name:       "malformed weights",
+			raw:        "testing:x,code:0,:2,documentation:-1,template:1:2,code",
+			categories: []string{"code"},
+			issues: []string{
+				"invalid category weight: testing:x",
+				"invalid category weight: code:0",
+				"invalid category weight: :2",
+				"invalid category weight: documentation:-1",
+				"invalid category weight: template:1:2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories, weights, issues := parseCategories(tt.raw)
+
+			assert.Equal(t, tt.categories, categories)
+			assert.Equal(t, tt.weights, weights)
+			require.Len(t, issues, len(tt.issues))
+
+			for i, issue := range issues {
+				assert.ErrorIs(t, issue, ErrInvalidCategoryWeight)
+				assert.EqualError(t, issue, tt.issues[i])
+			}
+		})
+	}
+}
+
+func TestCodeStyleArgs_ValidateCategoryWeights(t *testing.T) {
+	require.NoError(t, (&CodeStyleArgs{Categories: "testing:2,code"}).Validate(builtinCategories))
+
+	err := (&CodeStyleArgs{Categories: "testing:two"}).Validate(builtinCategories)
+
+	assert.ErrorIs(t, err, ErrInvalidCategoryWeight)
+	assert.NotErrorIs(t, err, ErrCategoriesRequired)
+
+	assert.ErrorIs(t, (&CodeStyleArgs{Categories: "unknown:2"}).Validate(builtinCategories), ErrInvalidCategory)
+}
+
+func TestGetRulesArgs_Validate(t *testing.T) {
+	tests := []struct {
+		wantErr error
+		name    string
+		args    GetRulesArgs
+	}{
+		{
+			name: "valid names",
+			args: GetRulesArgs{Names: "rule1, rule2"},
+		},
+		{
+			name: "valid names with markdown format",
+			args: GetRulesArgs{Names: "rule1", Format: FormatMarkdown},
+		},
+		{
+			name:    "empty names",
+			args:    GetRulesArgs{Names: " , "},
+			wantErr: ErrNamesRequired,
+		},
+		{
+			name:    "unsupported format",
+			args:    GetRulesArgs{Names: "rule1", Format: "html"},
+			wantErr: ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -1,23 +1,55 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/ksysoev/mcp-go-tools/pkg/api"
 	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
+// supportedConfigFormats lists the file extensions accepted for local config files.
+var supportedConfigFormats = []string{"yaml", "yml", "json", "toml"}
+
+// errUnsupportedConfigFormat is returned for local config files with an unsupported extension.
+var errUnsupportedConfigFormat = errors.New("unsupported config file format")
+
 // Config represents the complete application configuration structure.
 // It combines API service configuration and rule definitions loaded from
 // configuration files and environment variables.
 type Config struct {
-	// API holds the MCP server configuration
-	API api.Config `mapstructure:"api"`
+	// remote is set when the configuration was fetched over HTTP
+	remote *remoteConfig
+	// watcher is set when the local config file should be watched for changes
+	watcher *fileWatcher
+	// repoSettings holds the raw repository settings passed to registered repository factories
+	repoSettings map[string]any
+	// repoType selects the repository implementation, see newRepo
+	repoType string
 	// Rules defines the code generation rules and patterns
 	Rules static.Config `mapstructure:"rules"`
+	// Categories optionally declares the rule categories, see static.Options.Categories
+	Categories []static.Category `mapstructure:"categories"`
+	// Repository holds the rule repository settings
+	Repository static.Options `mapstructure:"repository"`
+	// API holds the MCP server configuration
+	API api.Config `mapstructure:"api"`
+	// noDefaults disables the default rules served when no rules are configured, see newRepo
+	noDefaults bool
+	// warmup primes the repository before serving requests, see warmup
+	warmup bool
 }
 
 // initConfig initializes the configuration from the specified file and environment.
@@ -25,17 +57,226 @@ type Config struct {
 // where environment variables override file settings. Environment variables
 // use underscore (_) as separator for nested fields (e.g., "api_port").
 //
+// Config paths starting with http:// or https:// are fetched over HTTP, bounded
+// by the configured timeout, and parsed according to the response content type
+// or, if that is not conclusive, the URL extension.
+//
+// With --config-watch, local config files are watched for changes, and with
+// --config-poll-interval their modification time is checked periodically. Both
+// have no effect on remote configs, which are refreshed with --config-refresh instead.
+//
+// YAML files may hold several documents separated by "---"; their rules are
+// concatenated, see mergeYAMLDocuments.
+//
+// Without a config path, the configuration is read from the environment only,
+// and the default rules are served unless disabled with --no-defaults.
+//
 // The function logs the final configuration at debug level for troubleshooting.
-// Returns error if the configuration file cannot be read or parsed.
+// Returns error if the configuration file has an unsupported extension, or cannot be read or parsed.
 func initConfig(arg *args) (*Config, error) {
+	if isRemoteConfig(arg.ConfigPath) {
+		if arg.ConfigWatch || arg.ConfigPoll > 0 {
+			slog.Warn("--config-watch and --config-poll-interval have no effect on remote configs, use --config-refresh instead")
+		}
+
+		remote := newRemoteConfig(arg.ConfigPath, arg.ConfigTimeout, arg.ConfigRefresh)
+		remote.noDefaults = arg.NoDefaults
+
+		cfg, err := remote.load(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config: %w", err)
+		}
+
+		return cfg, nil
+	}
+
+	if arg.ConfigPath == "" {
+		if arg.ConfigWatch || arg.ConfigPoll > 0 {
+			slog.Warn("--config-watch and --config-poll-interval have no effect without --config")
+		}
+
+		cfg, err := unmarshalConfig(viper.NewWithOptions())
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.noDefaults = arg.NoDefaults
+
+		return cfg, nil
+	}
+
+	if err := checkConfigFormat(arg.ConfigPath); err != nil {
+		return nil, err
+	}
+
 	v := viper.NewWithOptions()
 
 	v.SetConfigFile(arg.ConfigPath)
 
-	if err := v.ReadInConfig(); err != nil {
+	if err := readConfigFile(v); err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
+	cfg, err := unmarshalConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.noDefaults = arg.NoDefaults
+
+	if arg.ConfigWatch || arg.ConfigPoll > 0 {
+		cfg.watcher = &fileWatcher{
+			v:            v,
+			path:         arg.ConfigPath,
+			events:       arg.ConfigWatch,
+			pollInterval: arg.ConfigPoll,
+			noDefaults:   arg.NoDefaults,
+		}
+	}
+
+	return cfg, nil
+}
+
+// checkConfigFormat verifies that the config file extension is one of supportedConfigFormats.
+// Returns errUnsupportedConfigFormat with the list of supported formats otherwise.
+func checkConfigFormat(configPath string) error {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(configPath), "."))
+	if slices.Contains(supportedConfigFormats, ext) {
+		return nil
+	}
+
+	return fmt.Errorf("%w %q for %s: supported formats are %s",
+		errUnsupportedConfigFormat, ext, configPath, strings.Join(supportedConfigFormats, ", "))
+}
+
+// readConfigFile reads the config file set on v, including every document of
+// multi-document YAML files.
+func readConfigFile(v *viper.Viper) error {
+	if err := v.ReadInConfig(); err != nil {
+		return err
+	}
+
+	return mergeConfigFileDocuments(v)
+}
+
+// mergeConfigFileDocuments merges the documents following the first one of the
+// config file loaded into v, see mergeYAMLDocuments.
+func mergeConfigFileDocuments(v *viper.Viper) error {
+	path := v.ConfigFileUsed()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+
+	return mergeYAMLDocuments(v, format, data)
+}
+
+// mergeYAMLDocuments merges every document of multi-document YAML data into v,
+// which viper has loaded only the first document of. Rules are concatenated in
+// document order, while other settings of later documents override earlier ones.
+// Data in other formats, or holding a single document, is left to viper.
+// Returns error if a document cannot be parsed or its rules are not a list.
+func mergeYAMLDocuments(v *viper.Viper, format string, data []byte) error {
+	if format != "yaml" && format != "yml" {
+		return nil
+	}
+
+	var docs []map[string]any
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		var doc map[string]any
+
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to parse YAML document %d: %w", len(docs)+1, err)
+		}
+
+		docs = append(docs, doc)
+	}
+
+	if len(docs) < 2 {
+		return nil
+	}
+
+	var rules []any
+
+	for i, doc := range docs {
+		if i > 0 {
+			if err := v.MergeConfigMap(doc); err != nil {
+				return fmt.Errorf("failed to merge YAML document %d: %w", i+1, err)
+			}
+		}
+
+		if doc["rules"] == nil {
+			continue
+		}
+
+		docRules, ok := doc["rules"].([]any)
+		if !ok {
+			return fmt.Errorf("rules in YAML document %d must be a list", i+1)
+		}
+
+		rules = append(rules, docRules...)
+	}
+
+	return v.MergeConfigMap(map[string]any{"rules": rules})
+}
+
+// ruleEnabledEnv returns the environment variable that overrides the enabled flag
+// of the named rule: the name is upper-cased, every character other than a letter
+// or a digit is replaced with an underscore, and the result is wrapped as
+// RULES_<NAME>_ENABLED. For example, rule "error-wrapping" maps to RULES_ERROR_WRAPPING_ENABLED.
+func ruleEnabledEnv(name string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return unicode.ToUpper(r)
+		}
+
+		return '_'
+	}, name)
+
+	return "RULES_" + normalized + "_ENABLED"
+}
+
+// applyRuleEnabledEnv binds the enabled flag of every rule to its environment
+// variable and applies the values that are set.
+// Returns error if a variable does not hold a boolean.
+func applyRuleEnabledEnv(v *viper.Viper, rules static.Config) error {
+	for i := range rules {
+		env := ruleEnabledEnv(rules[i].Name)
+		key := strings.ToLower(env)
+
+		if err := v.BindEnv(key, env); err != nil {
+			return fmt.Errorf("failed to bind %s: %w", env, err)
+		}
+
+		if !v.IsSet(key) {
+			continue
+		}
+
+		enabled, err := strconv.ParseBool(v.GetString(key))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", env, err)
+		}
+
+		rules[i].Enabled = &enabled
+	}
+
+	return nil
+}
+
+// unmarshalConfig applies environment overrides to the loaded settings and
+// decodes them into a Config, including the repository type and raw settings. Rules can additionally be enabled or disabled
+// with RULES_<NAME>_ENABLED variables, see ruleEnabledEnv.
+// Returns error if the settings cannot be decoded.
+func unmarshalConfig(v *viper.Viper) (*Config, error) {
 	var cfg Config
 
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
@@ -45,6 +286,21 @@ func initConfig(arg *args) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := applyRuleEnabledEnv(v, cfg.Rules); err != nil {
+		return nil, err
+	}
+
+	cfg.Repository.Categories = cfg.Categories
+
+	for _, category := range cfg.Categories {
+		cfg.API.Categories = append(cfg.API.Categories, api.Category(category))
+	}
+
+	cfg.API.PrefixCategories = v.GetBool("repository.prefixCategories")
+
+	cfg.repoType = v.GetString("repository.type")
+	cfg.repoSettings = v.GetStringMap("repository")
+
 	slog.Debug("Config loaded", slog.Any("config", cfg))
 
 	return &cfg, nil
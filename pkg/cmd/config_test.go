@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/ksysoev/mcp-go-tools/pkg/api"
+	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -72,7 +77,14 @@ rules:
 `,
 			fileExt:      ".invalid",
 			wantError:    true,
-			errorMessage: "failed to read config",
+			errorMessage: `unsupported config file format "invalid"`,
+		},
+		{
+			name:         "missing extension",
+			fileContent:  "api: {}\n",
+			fileExt:      "",
+			wantError:    true,
+			errorMessage: "supported formats are yaml, yml, json, toml",
 		},
 	}
 
@@ -108,3 +120,300 @@ rules:
 		})
 	}
 }
+
+func TestInitConfigExampleMetadata(t *testing.T) {
+	configContent := `
+rules:
+  - name: "test_rule"
+    category: "code"
+    examples:
+      - description: "Anti-pattern"
+        code: "panic(err)"
+        metadata:
+          good: false
+          weight: 3
+          tags: "errors"
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o600))
+
+	cfg, err := initConfig(&args{ConfigPath: configPath})
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 1)
+	require.Len(t, cfg.Rules[0].Examples, 1)
+
+	metadata := cfg.Rules[0].Examples[0].Metadata
+	assert.Equal(t, false, metadata["good"])
+	assert.Equal(t, 3, metadata["weight"])
+	assert.Equal(t, "errors", metadata["tags"])
+}
+
+func TestInitConfigRemote(t *testing.T) {
+	const yamlConfig = `
+rules:
+  - name: "remote_rule"
+    category: "testing"
+`
+
+	const jsonConfig = `{"rules": [{"name": "remote_rule", "category": "testing"}]}`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rules.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(yamlConfig))
+	})
+	mux.HandleFunc("/rules", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write([]byte(jsonConfig))
+	})
+	mux.HandleFunc("/unknown", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte(yamlConfig))
+	})
+	mux.HandleFunc("/slow.yaml", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name         string
+		path         string
+		errorMessage string
+		timeout      time.Duration
+		wantError    bool
+	}{
+		{
+			name:    "yaml by extension",
+			path:    "/rules.yaml",
+			timeout: time.Second,
+		},
+		{
+			name:    "json by content type",
+			path:    "/rules",
+			timeout: time.Second,
+		},
+		{
+			name:         "unknown format",
+			path:         "/unknown",
+			timeout:      time.Second,
+			wantError:    true,
+			errorMessage: "unable to determine config format",
+		},
+		{
+			name:         "not found",
+			path:         "/missing.yaml",
+			timeout:      time.Second,
+			wantError:    true,
+			errorMessage: "unexpected status 404",
+		},
+		{
+			name:         "timeout",
+			path:         "/slow.yaml",
+			timeout:      50 * time.Millisecond,
+			wantError:    true,
+			errorMessage: "failed to fetch config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := initConfig(&args{
+				ConfigPath:    server.URL + tt.path,
+				ConfigTimeout: tt.timeout,
+			})
+
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMessage)
+				assert.Nil(t, cfg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, cfg.Rules, 1)
+			assert.Equal(t, "remote_rule", cfg.Rules[0].Name)
+		})
+	}
+}
+
+func TestRuleEnabledEnv(t *testing.T) {
+	assert.Equal(t, "RULES_ERROR_WRAPPING_ENABLED", ruleEnabledEnv("error-wrapping"))
+	assert.Equal(t, "RULES_TABLE_TESTS_ENABLED", ruleEnabledEnv("table_tests"))
+	assert.Equal(t, "RULES_GO_DOC_V2_ENABLED", ruleEnabledEnv("Go doc.v2"))
+}
+
+func TestInitConfigRuleEnabledEnv(t *testing.T) {
+	configContent := `
+rules:
+  - name: "error-wrapping"
+    category: "code"
+  - name: "table_tests"
+    category: "testing"
+    enabled: false
+  - name: "untouched"
+    category: "code"
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o600))
+
+	t.Setenv("RULES_ERROR_WRAPPING_ENABLED", "false")
+	t.Setenv("RULES_TABLE_TESTS_ENABLED", "true")
+
+	cfg, err := initConfig(&args{ConfigPath: configPath})
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 3)
+
+	assert.False(t, cfg.Rules[0].IsEnabled())
+	assert.True(t, cfg.Rules[1].IsEnabled())
+	assert.Nil(t, cfg.Rules[2].Enabled)
+	assert.True(t, cfg.Rules[2].IsEnabled())
+
+	t.Setenv("RULES_UNTOUCHED_ENABLED", "maybe")
+
+	_, err = initConfig(&args{ConfigPath: configPath})
+	assert.ErrorContains(t, err, "invalid RULES_UNTOUCHED_ENABLED")
+}
+
+func TestInitConfigMultiDocumentYAML(t *testing.T) {
+	configContent := `
+api:
+  defaultCategories: ["code"]
+rules:
+  - name: "error_wrapping"
+    category: "code"
+---
+rules:
+  - name: "table_tests"
+    category: "testing"
+  - name: "subtests"
+    category: "testing"
+---
+api:
+  maxResponseBytes: 1024
+rules:
+  - name: "package_docs"
+    category: "documentation"
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o600))
+
+	cfg, err := initConfig(&args{ConfigPath: configPath})
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		names = append(names, rule.Name)
+	}
+
+	assert.Equal(t, []string{"error_wrapping", "table_tests", "subtests", "package_docs"}, names)
+	assert.Equal(t, []string{"code"}, cfg.API.DefaultCategories)
+	assert.Equal(t, 1024, cfg.API.MaxResponseBytes)
+}
+
+func TestInitConfigMultiDocumentYAMLInvalidRules(t *testing.T) {
+	configContent := `
+rules:
+  - name: "error_wrapping"
+    category: "code"
+---
+rules: "table_tests"
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o600))
+
+	_, err := initConfig(&args{ConfigPath: configPath})
+	assert.ErrorContains(t, err, "rules in YAML document 2 must be a list")
+}
+
+func TestInitConfigCategories(t *testing.T) {
+	configContent := `
+categories:
+  - name: "testing"
+    displayName: "Testing"
+    description: "Testing conventions"
+rules:
+  - name: "table_tests"
+    category: "testing"
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o600))
+
+	cfg, err := initConfig(&args{ConfigPath: configPath})
+	require.NoError(t, err)
+
+	want := []static.Category{{Name: "testing", DisplayName: "Testing", Description: "Testing conventions"}}
+	assert.Equal(t, want, cfg.Categories)
+	assert.Equal(t, want, cfg.Repository.Categories)
+	assert.Equal(t, []api.Category{{Name: "testing", DisplayName: "Testing", Description: "Testing conventions"}}, cfg.API.Categories)
+}
+
+func TestInitConfigLanguageFallback(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	config := "api:\n  languageFallback:\n    typescript: javascript\n    javascript: \"\"\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0o600))
+
+	cfg, err := initConfig(&args{ConfigPath: configPath})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"typescript": "javascript", "javascript": ""}, cfg.API.LanguageFallback)
+}
+
+func TestInitConfigPrefixCategories(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("repository:\n  prefixCategories: true\n"), 0o600))
+
+	cfg, err := initConfig(&args{ConfigPath: configPath})
+	require.NoError(t, err)
+	assert.True(t, cfg.API.PrefixCategories)
+
+	cfg, err = initConfig(&args{})
+	require.NoError(t, err)
+	assert.False(t, cfg.API.PrefixCategories)
+}
+
+func TestInitConfigPrompts(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []api.Prompt
+	}{
+		{
+			name:   "not configured",
+			config: "rules: []\n",
+		},
+		{
+			name:   "disabled",
+			config: "api:\n  prompts: []\n",
+			want:   []api.Prompt{},
+		},
+		{
+			name: "configured",
+			config: "api:\n  prompts:\n" +
+				"    - name: concurrency_review\n" +
+				"      description: Review concurrent code\n" +
+				"      instruction: Review the following code for data races.\n" +
+				"      categories: code/concurrency\n",
+			want: []api.Prompt{{
+				Name:        "concurrency_review",
+				Description: "Review concurrent code",
+				Instruction: "Review the following code for data races.",
+				Categories:  "code/concurrency",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.config), 0o600))
+
+			cfg, err := initConfig(&args{ConfigPath: configPath})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.API.Prompts)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
+	"github.com/spf13/viper"
+)
+
+// defaultRulesYAML holds the curated Go rules served when no rules are configured.
+//
+//go:embed defaults.yaml
+var defaultRulesYAML []byte
+
+// defaultRules decodes the embedded default rules the same way as the rules of a config file.
+// Returns error if the embedded rules cannot be parsed.
+func defaultRules() (static.Config, error) {
+	v := viper.NewWithOptions()
+	v.SetConfigType("yaml")
+
+	if err := v.ReadConfig(bytes.NewReader(defaultRulesYAML)); err != nil {
+		return nil, fmt.Errorf("failed to read default rules: %w", err)
+	}
+
+	var rules static.Config
+	if err := v.UnmarshalKey("rules", &rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal default rules: %w", err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRules(t *testing.T) {
+	rules, err := defaultRules()
+	require.NoError(t, err)
+	require.NotEmpty(t, rules)
+
+	errs, warnings := static.Validate(&rules, &static.Options{})
+	assert.Empty(t, errs)
+	assert.Empty(t, warnings)
+
+	for _, rule := range rules {
+		assert.NotEmpty(t, rule.Description, rule.Name)
+		assert.NotEmpty(t, rule.Examples, rule.Name)
+		assert.Equal(t, "go", rule.Language, rule.Name)
+	}
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
+	"github.com/spf13/cobra"
+)
+
+// Output formats of the diff and validate commands.
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// diffArgs holds the command-line arguments of the diff command.
+type diffArgs struct {
+	From   string
+	To     string
+	Format string
+}
+
+// ruleRef identifies a rule in a diff. Rules are matched by category and name,
+// so moving a rule to another category shows up as a removal and an addition.
+type ruleRef struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// ruleChange describes a rule present in both configs with differing fields.
+type ruleChange struct {
+	ruleRef
+	Fields []string `json:"fields"`
+}
+
+// rulesDiff holds the differences between two rule sets.
+type rulesDiff struct {
+	Added    []ruleRef    `json:"added"`
+	Removed  []ruleRef    `json:"removed"`
+	Modified []ruleChange `json:"modified"`
+}
+
+// newDiffCommand creates the diff subcommand, which compares the rules of two configs.
+func newDiffCommand() *cobra.Command {
+	arg := &diffArgs{}
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the rules of two configs",
+		Long:  "Report rules added, removed and modified between two configs, matched by category and name",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if arg.Format != outputFormatText && arg.Format != outputFormatJSON {
+				return fmt.Errorf("unsupported diff format %q: supported formats are %s, %s", arg.Format, outputFormatText, outputFormatJSON)
+			}
+
+			from, err := initConfig(&args{ConfigPath: arg.From, ConfigTimeout: defaultConfigTimeout})
+			if err != nil {
+				return fmt.Errorf("load --from config: %w", err)
+			}
+
+			to, err := initConfig(&args{ConfigPath: arg.To, ConfigTimeout: defaultConfigTimeout})
+			if err != nil {
+				return fmt.Errorf("load --to config: %w", err)
+			}
+
+			diff := diffRules(from.Rules, to.Rules)
+
+			if arg.Format == outputFormatJSON {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(diff)
+			}
+
+			return diff.writeText(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVar(&arg.From, "from", "", "previous config file path or http(s) URL")
+	cmd.Flags().StringVar(&arg.To, "to", "", "updated config file path or http(s) URL")
+	cmd.Flags().StringVar(&arg.Format, "format", outputFormatText, "output format (text, json)")
+
+	_ = cmd.MarkFlagRequired("from") // flags are defined above
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+// diffRules compares two rule sets. Added and modified rules are listed in the
+// order of the updated set, removed rules in the order of the previous one.
+func diffRules(from, to static.Config) rulesDiff {
+	diff := rulesDiff{
+		Added:    []ruleRef{},
+		Removed:  []ruleRef{},
+		Modified: []ruleChange{},
+	}
+
+	previous := make(map[ruleRef]static.Rule, len(from))
+	for _, rule := range from {
+		previous[ruleRef{Name: rule.Name, Category: rule.Category}] = rule
+	}
+
+	updated := make(map[ruleRef]bool, len(to))
+
+	for _, rule := range to {
+		ref := ruleRef{Name: rule.Name, Category: rule.Category}
+		updated[ref] = true
+
+		old, ok := previous[ref]
+		if !ok {
+			diff.Added = append(diff.Added, ref)
+			continue
+		}
+
+		if fields := changedFields(old, rule); len(fields) > 0 {
+			diff.Modified = append(diff.Modified, ruleChange{ruleRef: ref, Fields: fields})
+		}
+	}
+
+	for _, rule := range from {
+		if ref := (ruleRef{Name: rule.Name, Category: rule.Category}); !updated[ref] {
+			diff.Removed = append(diff.Removed, ref)
+		}
+	}
+
+	return diff
+}
+
+// changedFields returns the config keys of the fields that differ between two versions of a rule.
+func changedFields(a, b static.Rule) []string {
+	var fields []string
+
+	if a.Description != b.Description {
+		fields = append(fields, "description")
+	}
+
+	if !strings.EqualFold(a.Severity, b.Severity) {
+		fields = append(fields, "severity")
+	}
+
+	if a.IsEnabled() != b.IsEnabled() {
+		fields = append(fields, "enabled")
+	}
+
+	if a.Deprecated != b.Deprecated {
+		fields = append(fields, "deprecated")
+	}
+
+	if a.Language != b.Language {
+		fields = append(fields, "language")
+	}
+
+	if a.DeprecationNote != b.DeprecationNote {
+		fields = append(fields, "deprecationNote")
+	}
+
+	if a.UpdatedAt != b.UpdatedAt {
+		fields = append(fields, "updatedAt")
+	}
+
+	if !slices.Equal(a.ConflictsWith, b.ConflictsWith) {
+		fields = append(fields, "conflictsWith")
+	}
+
+	if a.Order != b.Order {
+		fields = append(fields, "order")
+	}
+
+	if a.Template != b.Template {
+		fields = append(fields, "template")
+	}
+
+	if !reflect.DeepEqual(a.Examples, b.Examples) {
+		fields = append(fields, "examples")
+	}
+
+	return fields
+}
+
+// writeText writes a human-readable report of the diff.
+func (d rulesDiff) writeText(w io.Writer) error {
+	var sb strings.Builder
+
+	if len(d.Added)+len(d.Removed)+len(d.Modified) == 0 {
+		sb.WriteString("No changes\n")
+	}
+
+	for _, ref := range d.Added {
+		fmt.Fprintf(&sb, "+ %s [%s]\n", ref.Name, ref.Category)
+	}
+
+	for _, ref := range d.Removed {
+		fmt.Fprintf(&sb, "- %s [%s]\n", ref.Name, ref.Category)
+	}
+
+	for _, change := range d.Modified {
+		fmt.Fprintf(&sb, "~ %s [%s]: %s\n", change.Name, change.Category, strings.Join(change.Fields, ", "))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRules(t *testing.T) {
+	from := static.Config{
+		{Name: "kept", Category: "code", Description: "Same"},
+		{Name: "changed", Category: "code", Description: "Old", Severity: "should", Examples: []static.Example{{Code: "a"}}},
+		{Name: "removed", Category: "testing"},
+		{Name: "moved", Category: "testing"},
+		{Name: "case_only", Category: "code", Severity: "MUST"},
+	}
+
+	to := static.Config{
+		{Name: "added", Category: "documentation"},
+		{Name: "changed", Category: "code", Description: "New", Severity: "must", Examples: []static.Example{{Code: "b"}}},
+		{Name: "kept", Category: "code", Description: "Same"},
+		{Name: "moved", Category: "code"},
+		{Name: "case_only", Category: "code", Severity: "must"},
+	}
+
+	diff := diffRules(from, to)
+
+	assert.Equal(t, []ruleRef{
+		{Name: "added", Category: "documentation"},
+		{Name: "moved", Category: "code"},
+	}, diff.Added)
+	assert.Equal(t, []ruleRef{
+		{Name: "removed", Category: "testing"},
+		{Name: "moved", Category: "testing"},
+	}, diff.Removed)
+	assert.Equal(t, []ruleChange{
+		{ruleRef: ruleRef{Name: "changed", Category: "code"}, Fields: []string{"description", "severity", "examples"}},
+	}, diff.Modified)
+}
+
+func TestDiffCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	fromPath := filepath.Join(tmpDir, "from.yaml")
+	require.NoError(t, os.WriteFile(fromPath, []byte(`
+rules:
+  - name: "kept"
+    category: "code"
+  - name: "changed"
+    category: "code"
+    description: "Old"
+  - name: "removed"
+    category: "testing"
+`), 0o600))
+
+	toPath := filepath.Join(tmpDir, "to.yaml")
+	require.NoError(t, os.WriteFile(toPath, []byte(`
+rules:
+  - name: "kept"
+    category: "code"
+  - name: "changed"
+    category: "code"
+    description: "New"
+  - name: "added"
+    category: "documentation"
+`), 0o600))
+
+	tests := []struct {
+		name      string
+		want      string
+		args      []string
+		wantError bool
+		wantJSON  bool
+	}{
+		{
+			name: "text output",
+			args: []string{"diff", "--from", fromPath, "--to", toPath},
+			want: "+ added [documentation]\n- removed [testing]\n~ changed [code]: description\n",
+		},
+		{
+			name:     "json output",
+			args:     []string{"diff", "--from", fromPath, "--to", toPath, "--format", "json"},
+			want:     `{"added":[{"name":"added","category":"documentation"}],"removed":[{"name":"removed","category":"testing"}],"modified":[{"name":"changed","category":"code","fields":["description"]}]}`,
+			wantJSON: true,
+		},
+		{
+			name: "no changes",
+			args: []string{"diff", "--from", fromPath, "--to", fromPath},
+			want: "No changes\n",
+		},
+		{
+			name:      "unsupported format",
+			args:      []string{"diff", "--from", fromPath, "--to", toPath, "--format", "xml"},
+			wantError: true,
+		},
+		{
+			name:      "missing to flag",
+			args:      []string{"diff", "--from", fromPath},
+			wantError: true,
+		},
+		{
+			name:      "invalid from config",
+			args:      []string{"diff", "--from", filepath.Join(tmpDir, "nonexistent.yaml"), "--to", toPath},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := InitCommands("test", "1.0.0")
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(tt.args)
+
+			err = cmd.Execute()
+
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tt.wantJSON {
+				assert.JSONEq(t, tt.want, out.String())
+			} else {
+				assert.Equal(t, tt.want, out.String())
+			}
+		})
+	}
+}
@@ -2,65 +2,175 @@
 //
 // This file provides logging configuration and initialization using slog.
 // Logging features include:
-// - JSON and text output formats
+// - JSON, text and logfmt output formats
 // - Configurable log levels (debug, info, warn, error)
+// - Output to stderr, keeping stdout free for the MCP stdio transport
 // - File output support with automatic file creation
 // - Version and application tagging for all log entries
+// - Runtime toggling of debug level via SIGUSR1 on Unix systems
+// - Flushing and closing the log file on shutdown
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 )
 
+// Supported log output formats.
+const (
+	logFormatJSON   = "json"
+	logFormatText   = "text"
+	logFormatLogfmt = "logfmt"
+)
+
+// appLogger holds the state of the application logger set up by initLogger.
+type appLogger struct {
+	// level controls the active level and can be changed at runtime
+	level *slog.LevelVar
+	// file is the log file, nil when logging to stderr
+	file *os.File
+	// fallback writes to stderr once the log file is closed
+	fallback *slog.Logger
+}
+
 // initLogger initializes the default logger for the application using slog.
 // It configures the logger based on command-line arguments:
 //   - LogLevel: Sets the minimum log level (debug, info, warn, error)
-//   - TextFormat: Uses human-readable format instead of JSON
-//   - LogFile: Writes logs to specified file
+//   - Quiet: Raises the minimum log level to error, overriding LogLevel
+//   - LogFormat: Output format (json, text, logfmt)
+//   - TextFormat: Alias for the text format, kept for backward compatibility
+//   - LogFile: Writes logs to specified file instead of stderr
 //
 // The logger adds version and application tags to all log entries.
-// Returns error if log level is invalid or file access fails.
-func initLogger(arg *args) error {
+// The returned logger must be closed on shutdown to flush the log file.
+// Returns error if log level or format is invalid or file access fails.
+func initLogger(arg *args) (*appLogger, error) {
 	var logLevel slog.Level
 	err := logLevel.UnmarshalText([]byte(arg.LogLevel))
 
 	if err != nil {
-		return fmt.Errorf("invalid log level: %w", err)
+		return nil, fmt.Errorf("invalid log level: %w", err)
+	}
+
+	if arg.Quiet {
+		logLevel = slog.LevelError
+	}
+
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(logLevel)
+
+	newHandler, err := handlerFactory(arg)
+	if err != nil {
+		return nil, err
 	}
 
 	options := &slog.HandlerOptions{
-		Level: logLevel,
+		Level: levelVar,
 	}
 
-	// Set up writer based on logfile flag
-	var writer = io.Discard
+	// The server speaks MCP over stdio, so stdout is reserved for the protocol
+	// stream and logs go to stderr unless a log file is set.
+	var writer io.Writer = os.Stderr
+
+	l := &appLogger{level: levelVar}
 
 	// Open log file if specified
 	if arg.LogFile != "" {
-		writer, err = os.OpenFile(arg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		l.file, err = os.OpenFile(arg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
 
 		if err != nil {
-			return fmt.Errorf("failed to open log file: %w", err)
+			return nil, fmt.Errorf("failed to open log file: %w", err)
 		}
-	}
 
-	// Create handler based on format
-	var logHandler slog.Handler
-	if arg.TextFormat {
-		logHandler = slog.NewTextHandler(writer, options)
-	} else {
-		logHandler = slog.NewJSONHandler(writer, options)
+		writer = l.file
 	}
 
-	logger := slog.New(logHandler).With(
+	tags := []any{
 		slog.String("ver", arg.version),
-		slog.String("app", "mcp-go-tools"),
-	)
+		slog.String("app", appName),
+	}
+
+	logger := slog.New(newHandler(writer, options)).With(tags...)
+	l.fallback = slog.New(newHandler(os.Stderr, options)).With(tags...)
 
 	slog.SetDefault(logger)
 
+	return l, nil
+}
+
+// close flushes and closes the log file, if any. The default logger is switched
+// to stderr first, so that errors reported later during shutdown are not lost.
+// Returns error if the log file cannot be flushed or closed.
+func (l *appLogger) close() error {
+	if l.file == nil {
+		return nil
+	}
+
+	slog.SetDefault(l.fallback)
+
+	if err := l.file.Sync(); err != nil {
+		_ = l.file.Close()
+		return fmt.Errorf("failed to flush log file: %w", err)
+	}
+
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
 	return nil
 }
+
+// handlerFactory returns the slog handler constructor for the configured log format.
+// The legacy TextFormat flag selects the text format in place of the default LogFormat.
+// Returns error if the log format is not supported, or if TextFormat is combined with
+// an explicit LogFormat other than text.
+func handlerFactory(arg *args) (func(io.Writer, *slog.HandlerOptions) slog.Handler, error) {
+	format := arg.LogFormat
+	if arg.TextFormat {
+		if arg.logFormatSet && format != logFormatText {
+			return nil, fmt.Errorf("--log-text conflicts with --log-format %s", format)
+		}
+
+		format = logFormatText
+	}
+
+	switch format {
+	case "", logFormatJSON:
+		return func(w io.Writer, opts *slog.HandlerOptions) slog.Handler { return slog.NewJSONHandler(w, opts) }, nil
+	case logFormatText, logFormatLogfmt:
+		// slog's text handler emits key=value pairs in logfmt syntax
+		return func(w io.Writer, opts *slog.HandlerOptions) slog.Handler { return slog.NewTextHandler(w, opts) }, nil
+	default:
+		return nil, fmt.Errorf("invalid log format: %s", format)
+	}
+}
+
+// watchLogLevel toggles debug logging every time a signal is received on sigCh.
+// The level switches between debug and base, so a first signal enables debug
+// output and a second one restores the configured level.
+// The change is logged at the new level, so that it is visible even when the base
+// level suppresses info messages.
+// It blocks until the context is cancelled or sigCh is closed.
+func watchLogLevel(ctx context.Context, levelVar *slog.LevelVar, base slog.Level, sigCh <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-sigCh:
+			if !ok {
+				return
+			}
+
+			if levelVar.Level() == slog.LevelDebug {
+				levelVar.Set(base)
+			} else {
+				levelVar.Set(slog.LevelDebug)
+			}
+
+			slog.Log(ctx, levelVar.Level(), "Log level changed", slog.String("level", levelVar.Level().String()))
+		}
+	}
+}
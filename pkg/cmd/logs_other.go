@@ -0,0 +1,11 @@
+//go:build !unix
+
+package cmd
+
+import (
+	"context"
+	"log/slog"
+)
+
+// notifyLogLevelToggle is a no-op on platforms without SIGUSR1.
+func notifyLogLevelToggle(_ context.Context, _ *slog.LevelVar) {}
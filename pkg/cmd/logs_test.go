@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"context"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -60,7 +63,7 @@ func TestLoggerFileHandling(t *testing.T) {
 				LogFile:    logFile,
 			}
 
-			err := initLogger(args)
+			_, err := initLogger(args)
 			if tt.wantError {
 				assert.Error(t, err)
 				return
@@ -81,3 +84,306 @@ func TestLoggerFileHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestInitLoggerLevelVar(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	logFile := filepath.Join(t.TempDir(), "test.log")
+
+	logger, err := initLogger(&args{
+		LogLevel:   "info",
+		TextFormat: true,
+		LogFile:    logFile,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, logger)
+	assert.Equal(t, slog.LevelInfo, logger.level.Level())
+
+	slog.Debug("hidden debug message")
+
+	logger.level.Set(slog.LevelDebug)
+	slog.Debug("visible debug message")
+
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "hidden debug message")
+	assert.Contains(t, string(content), "visible debug message")
+}
+
+func TestInitLoggerQuiet(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	logFile := filepath.Join(t.TempDir(), "test.log")
+
+	logger, err := initLogger(&args{
+		LogLevel: "debug",
+		LogFile:  logFile,
+		Quiet:    true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelError, logger.level.Level())
+
+	slog.Info("startup message")
+	slog.Error("failure message")
+
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "startup message")
+	assert.Contains(t, string(content), "failure message")
+}
+
+func TestInitLoggerQuietInvalidLevel(t *testing.T) {
+	_, err := initLogger(&args{LogLevel: "invalid", Quiet: true})
+	assert.ErrorContains(t, err, "invalid log level")
+}
+
+func TestInitLoggerStdoutClean(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	t.Cleanup(func() { os.Stdout = stdout })
+
+	_, err = initLogger(&args{LogLevel: "info", Quiet: true})
+	require.NoError(t, err)
+
+	slog.Info("startup message")
+	slog.Error("failure message")
+
+	os.Stdout = stdout
+	require.NoError(t, w.Close())
+
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Empty(t, out, "stdout is reserved for the MCP stdio transport")
+}
+
+func TestAppLoggerClose(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	logFile := filepath.Join(t.TempDir(), "test.log")
+
+	logger, err := initLogger(&args{LogLevel: "info", LogFile: logFile})
+	require.NoError(t, err)
+
+	slog.Info("final message")
+	require.NoError(t, logger.close())
+
+	// Logging after close must not write to the closed file
+	slog.Info("after close")
+
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "final message")
+	assert.NotContains(t, string(content), "after close")
+	assert.ErrorIs(t, logger.file.Close(), os.ErrClosed)
+}
+
+func TestAppLoggerCloseWithoutFile(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	logger, err := initLogger(&args{LogLevel: "info"})
+	require.NoError(t, err)
+	assert.NoError(t, logger.close())
+}
+
+func TestInitLoggerDefaultsToStderr(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	stdoutR, stdoutW, err := os.Pipe()
+	require.NoError(t, err)
+
+	stderrR, stderrW, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutW, stderrW
+
+	t.Cleanup(func() { os.Stdout, os.Stderr = stdout, stderr })
+
+	_, err = initLogger(&args{LogLevel: "info"})
+	require.NoError(t, err)
+
+	slog.Info("startup message")
+
+	os.Stdout, os.Stderr = stdout, stderr
+	require.NoError(t, stdoutW.Close())
+	require.NoError(t, stderrW.Close())
+
+	out, err := io.ReadAll(stdoutR)
+	require.NoError(t, err)
+	assert.Empty(t, out, "stdout is reserved for the MCP stdio transport")
+
+	logs, err := io.ReadAll(stderrR)
+	require.NoError(t, err)
+	assert.Contains(t, string(logs), "startup message")
+}
+
+func TestWatchLogLevel(t *testing.T) {
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(slog.LevelWarn)
+
+	sigCh := make(chan os.Signal)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		watchLogLevel(context.Background(), levelVar, slog.LevelWarn, sigCh)
+	}()
+
+	sigCh <- os.Interrupt
+	sigCh <- os.Interrupt
+	sigCh <- os.Interrupt
+
+	close(sigCh)
+	<-done
+
+	assert.Equal(t, slog.LevelDebug, levelVar.Level())
+}
+
+func TestWatchLogLevelLogsChangeAtNewLevel(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(slog.LevelError)
+
+	var buf strings.Builder
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: levelVar})))
+
+	sigCh := make(chan os.Signal)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		watchLogLevel(context.Background(), levelVar, slog.LevelError, sigCh)
+	}()
+
+	sigCh <- os.Interrupt
+	sigCh <- os.Interrupt
+
+	close(sigCh)
+	<-done
+
+	assert.Contains(t, buf.String(), `level=DEBUG msg="Log level changed" level=DEBUG`)
+	assert.Contains(t, buf.String(), `level=ERROR msg="Log level changed" level=ERROR`)
+}
+
+func TestWatchLogLevelContextCancelled(t *testing.T) {
+	levelVar := new(slog.LevelVar)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		watchLogLevel(ctx, levelVar, slog.LevelInfo, make(chan os.Signal))
+	}()
+
+	<-done
+
+	assert.Equal(t, slog.LevelInfo, levelVar.Level())
+}
+
+func TestInitLoggerFormat(t *testing.T) {
+	tests := []struct {
+		name         string
+		logFormat    string
+		wantPrefix   string
+		wantErr      string
+		textFormat   bool
+		logFormatSet bool
+	}{
+		{
+			name:       "default json",
+			logFormat:  "",
+			wantPrefix: "{",
+		},
+		{
+			name:       "json",
+			logFormat:  "json",
+			wantPrefix: "{",
+		},
+		{
+			name:       "text",
+			logFormat:  "text",
+			wantPrefix: "time=",
+		},
+		{
+			name:       "logfmt",
+			logFormat:  "logfmt",
+			wantPrefix: "time=",
+		},
+		{
+			name:       "log-text alias overrides default format",
+			logFormat:  "json",
+			textFormat: true,
+			wantPrefix: "time=",
+		},
+		{
+			name:         "log-text alias with explicit text format",
+			logFormat:    "text",
+			logFormatSet: true,
+			textFormat:   true,
+			wantPrefix:   "time=",
+		},
+		{
+			name:         "log-text alias conflicts with explicit format",
+			logFormat:    "json",
+			logFormatSet: true,
+			textFormat:   true,
+			wantErr:      "--log-text conflicts with --log-format json",
+		},
+		{
+			name:      "invalid format",
+			logFormat: "xml",
+			wantErr:   "invalid log format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultLogger := slog.Default()
+			t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+			logFile := filepath.Join(t.TempDir(), "test.log")
+
+			_, err := initLogger(&args{
+				LogLevel:     "info",
+				LogFormat:    tt.logFormat,
+				TextFormat:   tt.textFormat,
+				LogFile:      logFile,
+				logFormatSet: tt.logFormatSet,
+			})
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			slog.Info("test message", slog.String("key", "value"))
+
+			content, err := os.ReadFile(logFile)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(string(content), tt.wantPrefix), "unexpected log output: %s", content)
+		})
+	}
+}
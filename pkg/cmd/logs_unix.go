@@ -0,0 +1,26 @@
+//go:build unix
+
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyLogLevelToggle starts a goroutine that toggles debug logging on SIGUSR1.
+// The signal subscription is released when the context is cancelled.
+func notifyLogLevelToggle(ctx context.Context, levelVar *slog.LevelVar) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1)
+
+	base := levelVar.Level()
+
+	go func() {
+		defer signal.Stop(sigCh)
+
+		watchLogLevel(ctx, levelVar, base, sigCh)
+	}()
+}
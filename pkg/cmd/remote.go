@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/spf13/viper"
+)
+
+// errNotModified is returned by remoteConfig.load when the server reports
+// that the configuration has not changed since the last fetch.
+var errNotModified = errors.New("config not modified")
+
+// contentTypeFormats maps config response media types to viper config types.
+var contentTypeFormats = map[string]string{
+	"application/json":   "json",
+	"application/yaml":   "yaml",
+	"application/x-yaml": "yaml",
+	"text/yaml":          "yaml",
+	"text/x-yaml":        "yaml",
+	"application/toml":   "toml",
+}
+
+// repoSetter replaces the repository used to serve rules.
+// It is implemented by core.Service.
+type repoSetter interface {
+	SetRepo(resource core.ResourceRepo)
+}
+
+// remoteConfig fetches configuration over HTTP. It remembers the ETag and
+// Last-Modified validators of the last successful fetch so that later fetches
+// are conditional, and the hash of its body so that unchanged configs from servers
+// without validators are not parsed again either.
+// It is not safe for concurrent use.
+type remoteConfig struct {
+	url          string
+	etag         string
+	lastModified string
+	bodyHash     string
+	timeout      time.Duration
+	interval     time.Duration
+	// noDefaults is passed on to every loaded config, see Config.noDefaults
+	noDefaults bool
+}
+
+// newRemoteConfig creates a remoteConfig for rawURL. A zero timeout disables
+// the fetch deadline, and a zero interval disables periodic refresh.
+func newRemoteConfig(rawURL string, timeout, interval time.Duration) *remoteConfig {
+	return &remoteConfig{
+		url:      rawURL,
+		timeout:  timeout,
+		interval: interval,
+	}
+}
+
+// isRemoteConfig reports whether the config path is an HTTP(S) URL.
+func isRemoteConfig(configPath string) bool {
+	return strings.HasPrefix(configPath, "http://") || strings.HasPrefix(configPath, "https://")
+}
+
+// load fetches and parses the configuration.
+// Returns errNotModified if the server responds with 304 Not Modified or with the
+// same body as the last successful fetch, or error
+// if the request fails, the server responds with another non-200 status,
+// or the config cannot be parsed.
+func (rc *remoteConfig) load(ctx context.Context) (*Config, error) {
+	u, err := url.Parse(rc.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config URL: %w", err)
+	}
+
+	if rc.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, rc.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config request: %w", err)
+	}
+
+	if rc.etag != "" {
+		req.Header.Set("If-None-Match", rc.etag)
+	}
+
+	if rc.lastModified != "" {
+		req.Header.Set("If-Modified-Since", rc.lastModified)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, errNotModified
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch config: unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config body: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	if rc.bodyHash == string(sum[:]) {
+		// Keep the validators current, so the next requests can be answered with 304
+		rc.etag = resp.Header.Get("ETag")
+		rc.lastModified = resp.Header.Get("Last-Modified")
+
+		return nil, errNotModified
+	}
+
+	configType, err := remoteConfigType(resp.Header.Get("Content-Type"), u)
+	if err != nil {
+		return nil, err
+	}
+
+	v := viper.NewWithOptions()
+	v.SetConfigType(configType)
+
+	if err := v.ReadConfig(bytes.NewReader(body)); err != nil {
+		return nil, err
+	}
+
+	if err := mergeYAMLDocuments(v, configType, body); err != nil {
+		return nil, err
+	}
+
+	cfg, err := unmarshalConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.etag = resp.Header.Get("ETag")
+	rc.lastModified = resp.Header.Get("Last-Modified")
+	rc.bodyHash = string(sum[:])
+	cfg.remote = rc
+	cfg.noDefaults = rc.noDefaults
+
+	return cfg, nil
+}
+
+// watch periodically re-fetches the configuration and swaps the repository of
+// target when the rules change. Fetch and repository errors are logged and the
+// current repository is kept. Only rules and repository settings are reloaded.
+// It blocks until the context is cancelled.
+func (rc *remoteConfig) watch(ctx context.Context, target repoSetter) {
+	ticker := time.NewTicker(rc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rc.reload(ctx, target)
+		}
+	}
+}
+
+// reload fetches the configuration once and swaps the repository of target on change.
+func (rc *remoteConfig) reload(ctx context.Context, target repoSetter) {
+	cfg, err := rc.load(ctx)
+	if errors.Is(err, errNotModified) {
+		slog.Debug("Remote config not modified", slog.String("url", rc.url))
+		return
+	} else if err != nil {
+		slog.Error("Failed to reload remote config", slog.String("url", rc.url), slog.Any("error", err))
+		return
+	}
+
+	resource, err := newRepo(ctx, cfg)
+	if err != nil {
+		slog.Error("Failed to create repository from remote config", slog.String("url", rc.url), slog.Any("error", err))
+		return
+	}
+
+	target.SetRepo(resource)
+
+	slog.Info("Remote config reloaded", slog.String("url", rc.url), slog.Int("rules", len(cfg.Rules)))
+}
+
+// remoteConfigType determines the config format from the response content type,
+// falling back to the URL path extension.
+func remoteConfigType(contentType string, u *url.URL) (string, error) {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if format, ok := contentTypeFormats[mediaType]; ok {
+			return format, nil
+		}
+	}
+
+	if ext := strings.TrimPrefix(path.Ext(u.Path), "."); ext != "" {
+		return ext, nil
+	}
+
+	return "", errors.New("unable to determine config format from content type or URL extension")
+}
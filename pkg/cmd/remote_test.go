@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepoSetter struct {
+	repos []core.ResourceRepo
+	mu    sync.Mutex
+}
+
+func (f *fakeRepoSetter) SetRepo(resource core.ResourceRepo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.repos = append(f.repos, resource)
+}
+
+func (f *fakeRepoSetter) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.repos)
+}
+
+func newETagServer(t *testing.T, etag *string, body *string, mu *sync.Mutex) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("If-None-Match") == *etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", *etag)
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write([]byte(*body))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRemoteConfigReload(t *testing.T) {
+	var mu sync.Mutex
+
+	etag := `"v1"`
+	body := "rules:\n  - name: rule_v1\n    category: code\n"
+
+	server := newETagServer(t, &etag, &body, &mu)
+
+	remote := newRemoteConfig(server.URL, time.Second, 0)
+
+	cfg, err := remote.load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "rule_v1", cfg.Rules[0].Name)
+	assert.Equal(t, `"v1"`, remote.etag)
+
+	setter := &fakeRepoSetter{}
+
+	// Unchanged config must not swap the repository
+	remote.reload(context.Background(), setter)
+	assert.Equal(t, 0, setter.count())
+
+	mu.Lock()
+	etag = `"v2"`
+	body = "rules:\n  - name: rule_v2\n    category: code\n"
+	mu.Unlock()
+
+	remote.reload(context.Background(), setter)
+	require.Equal(t, 1, setter.count())
+	assert.Equal(t, `"v2"`, remote.etag)
+
+	rules, err := setter.repos[0].GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "rule_v2", rules[0].Name)
+}
+
+func TestRemoteConfigReloadWithoutValidators(t *testing.T) {
+	var mu sync.Mutex
+
+	body := "rules:\n  - name: rule_v1\n    category: code\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	remote := newRemoteConfig(server.URL, time.Second, 0)
+
+	_, err := remote.load(context.Background())
+	require.NoError(t, err)
+
+	setter := &fakeRepoSetter{}
+
+	// An identical body must not be parsed again or swap the repository
+	_, err = remote.load(context.Background())
+	require.ErrorIs(t, err, errNotModified)
+
+	remote.reload(context.Background(), setter)
+	assert.Equal(t, 0, setter.count())
+
+	mu.Lock()
+	body = "rules:\n  - name: rule_v2\n    category: code\n"
+	mu.Unlock()
+
+	remote.reload(context.Background(), setter)
+	assert.Equal(t, 1, setter.count())
+}
+
+func TestRemoteConfigRotatedETag(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		downloads int
+		version   = 1
+	)
+
+	body := "rules:\n  - name: rule_v1\n    category: code\n"
+
+	// The ETag changes on every version although the body stays the same
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		etag := fmt.Sprintf(`"v%d"`, version)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		downloads++
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	remote := newRemoteConfig(server.URL, time.Second, 0)
+
+	_, err := remote.load(context.Background())
+	require.NoError(t, err)
+
+	mu.Lock()
+	version = 2
+	mu.Unlock()
+
+	_, err = remote.load(context.Background())
+	require.ErrorIs(t, err, errNotModified)
+	assert.Equal(t, `"v2"`, remote.etag, "the rotated ETag must be stored for an unchanged body")
+
+	_, err = remote.load(context.Background())
+	require.ErrorIs(t, err, errNotModified)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, 2, downloads, "the unchanged body must not be downloaded again")
+}
+
+func TestRemoteConfigReloadError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	setter := &fakeRepoSetter{}
+
+	newRemoteConfig(server.URL+"/rules.yaml", time.Second, 0).reload(context.Background(), setter)
+
+	assert.Equal(t, 0, setter.count())
+}
+
+func TestRemoteConfigWatch(t *testing.T) {
+	var mu sync.Mutex
+
+	etag := `"v1"`
+	body := "rules:\n  - name: rule_v1\n    category: code\n"
+
+	server := newETagServer(t, &etag, &body, &mu)
+
+	remote := newRemoteConfig(server.URL, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	setter := &fakeRepoSetter{}
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		remote.watch(ctx, setter)
+	}()
+
+	assert.Eventually(t, func() bool { return setter.count() == 1 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
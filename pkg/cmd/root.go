@@ -4,18 +4,37 @@ package cmd
 import (
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/ksysoev/mcp-go-tools/pkg/api"
 	"github.com/spf13/cobra"
 )
 
+const (
+	// appName is the application name used in logs and reported to MCP clients.
+	appName = "mcp-go-tools"
+	// defaultConfigTimeout bounds fetching a remote config when --config-timeout is not set.
+	defaultConfigTimeout = 10 * time.Second
+)
+
 // args holds all command-line arguments and configuration options.
 type args struct {
-	build      string
-	version    string
-	LogLevel   string
-	ConfigPath string
-	LogFile    string
-	TextFormat bool
+	build         string
+	version       string
+	LogLevel      string
+	LogFormat     string
+	ConfigPath    string
+	LogFile       string
+	ConfigTimeout time.Duration
+	ConfigRefresh time.Duration
+	ConfigPoll    time.Duration
+	TextFormat    bool
+	ConfigWatch   bool
+	Quiet         bool
+	NoDefaults    bool
+	Warmup        bool
+	// logFormatSet reports whether --log-format was given explicitly rather than defaulted
+	logFormatSet bool
 }
 
 // InitCommands initializes and returns the root command for the MCP code tools server.
@@ -29,7 +48,7 @@ func InitCommands(build, version string) (*cobra.Command, error) {
 	}
 
 	cmd := &cobra.Command{
-		Use:     "mcp-go-tools",
+		Use:     appName,
 		Short:   "MCP code tools server",
 		Long:    "Model Context Protocol server for code generation tools",
 		Version: fmt.Sprintf("%s (Build: %s)", version, build),
@@ -41,10 +60,23 @@ func InitCommands(build, version string) (*cobra.Command, error) {
 		Short: "Start MCP code tools server",
 		Long:  "Start the Model Context Protocol server for code generation tools",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			if err := initLogger(args); err != nil {
+			args.logFormatSet = cmd.Flags().Changed("log-format")
+
+			logger, err := initLogger(args)
+			if err != nil {
 				return fmt.Errorf("init logger: %w", err)
 			}
 
+			defer func() {
+				slog.Info("Server stopped")
+
+				if err := logger.close(); err != nil {
+					slog.Error("Failed to close logger", slog.Any("error", err))
+				}
+			}()
+
+			notifyLogLevelToggle(cmd.Context(), logger.level)
+
 			slog.Info("Starting MCP code tools server",
 				slog.String("version", args.version),
 				slog.String("build", args.build))
@@ -54,17 +86,30 @@ func InitCommands(build, version string) (*cobra.Command, error) {
 				return fmt.Errorf("init config: %w", err)
 			}
 
-			return runStart(cmd.Context(), cfg)
+			cfg.warmup = args.Warmup
+
+			return runStart(cmd.Context(), cfg, api.ServerInfo{
+				Name:    appName,
+				Version: args.version,
+			})
 		},
 	}
 
 	// Add persistent flags
-	serverCmd.PersistentFlags().StringVar(&args.ConfigPath, "config", "", "config file path")
+	serverCmd.PersistentFlags().StringVar(&args.ConfigPath, "config", "", "config file path or http(s) URL")
+	serverCmd.PersistentFlags().DurationVar(&args.ConfigTimeout, "config-timeout", defaultConfigTimeout, "timeout for fetching remote config")
+	serverCmd.PersistentFlags().DurationVar(&args.ConfigRefresh, "config-refresh", 0, "interval for re-fetching remote config (0 disables refresh)")
+	serverCmd.PersistentFlags().BoolVar(&args.ConfigWatch, "config-watch", false, "reload rules when the local config file changes")
+	serverCmd.PersistentFlags().DurationVar(&args.ConfigPoll, "config-poll-interval", 0, "interval for checking the local config file for changes (0 disables polling)")
+	serverCmd.PersistentFlags().BoolVar(&args.NoDefaults, "no-defaults", false, "serve no rules instead of the default rules when none are configured")
+	serverCmd.PersistentFlags().BoolVar(&args.Warmup, "warmup", false, "prime the rule repository before serving requests to reduce first-request latency")
 	serverCmd.PersistentFlags().StringVar(&args.LogLevel, "log-level", "info", "log level (debug, info, warn, error)")
-	serverCmd.PersistentFlags().BoolVar(&args.TextFormat, "log-text", false, "log in text format, otherwise JSON")
-	serverCmd.PersistentFlags().StringVar(&args.LogFile, "log-file", "", "log file path (if not set, logs to stdout)")
+	serverCmd.PersistentFlags().BoolVar(&args.Quiet, "quiet", false, "only log errors, overrides --log-level")
+	serverCmd.PersistentFlags().StringVar(&args.LogFormat, "log-format", logFormatJSON, "log format (json, text, logfmt)")
+	serverCmd.PersistentFlags().BoolVar(&args.TextFormat, "log-text", false, "log in text format, alias for --log-format=text; cannot be combined with another --log-format")
+	serverCmd.PersistentFlags().StringVar(&args.LogFile, "log-file", "", "log file path (if not set, logs to stderr)")
 
-	cmd.AddCommand(serverCmd)
+	cmd.AddCommand(serverCmd, newDiffCommand(), newValidateCommand(), newSelfTestCommand())
 
 	return cmd, nil
 }
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,8 +56,11 @@ func TestInitCommands(t *testing.T) {
 
 			// Verify subcommands
 			subCmds := cmd.Commands()
-			require.Len(t, subCmds, 1)
-			serverCmd := subCmds[0]
+			require.Len(t, subCmds, 4)
+			assert.Equal(t, "diff", subCmds[0].Use)
+			assert.Equal(t, "selftest", subCmds[1].Use)
+			assert.Equal(t, "validate", subCmds[3].Use)
+			serverCmd := subCmds[2]
 			assert.Equal(t, "server", serverCmd.Use)
 			assert.Equal(t, "Start MCP code tools server", serverCmd.Short)
 
@@ -64,10 +71,30 @@ func TestInitCommands(t *testing.T) {
 			require.NotNil(t, configFlag)
 			assert.Equal(t, "", configFlag.DefValue)
 
+			configTimeoutFlag := flags.Lookup("config-timeout")
+			require.NotNil(t, configTimeoutFlag)
+			assert.Equal(t, "10s", configTimeoutFlag.DefValue)
+
+			configPollFlag := flags.Lookup("config-poll-interval")
+			require.NotNil(t, configPollFlag)
+			assert.Equal(t, "0s", configPollFlag.DefValue)
+
+			configWatchFlag := flags.Lookup("config-watch")
+			require.NotNil(t, configWatchFlag)
+			assert.Equal(t, "false", configWatchFlag.DefValue)
+
 			logLevelFlag := flags.Lookup("log-level")
 			require.NotNil(t, logLevelFlag)
 			assert.Equal(t, "info", logLevelFlag.DefValue)
 
+			quietFlag := flags.Lookup("quiet")
+			require.NotNil(t, quietFlag)
+			assert.Equal(t, "false", quietFlag.DefValue)
+
+			logFormatFlag := flags.Lookup("log-format")
+			require.NotNil(t, logFormatFlag)
+			assert.Equal(t, "json", logFormatFlag.DefValue)
+
 			logTextFlag := flags.Lookup("log-text")
 			require.NotNil(t, logTextFlag)
 			assert.Equal(t, "false", logTextFlag.DefValue)
@@ -107,11 +134,6 @@ rules:
 		args      []string
 		wantError bool
 	}{
-		{
-			name:      "missing config flag",
-			args:      []string{"server"},
-			wantError: true,
-		},
 		{
 			name: "invalid log level",
 			args: []string{
@@ -138,6 +160,16 @@ rules:
 			},
 			wantError: true,
 		},
+		{
+			name: "log-text with explicit log format",
+			args: []string{
+				"server",
+				"--config", configPath,
+				"--log-format", "json",
+				"--log-text",
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -169,6 +201,34 @@ rules:
 	}
 }
 
+func TestServerCommandShutdownLog(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("rules:\n  - name: rule\n    category: code\n"), 0o600))
+
+	logFile := filepath.Join(tmpDir, "server.log")
+
+	cmd, err := InitCommands("test", "1.0.0")
+	require.NoError(t, err)
+
+	cmd.SetArgs([]string{"server", "--config", configPath, "--log-file", logFile})
+
+	// Cancelling the context mirrors the signal-triggered shutdown in main
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	require.NoError(t, cmd.ExecuteContext(ctx))
+
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Contains(t, lines[len(lines)-1], "Server stopped")
+}
+
 func TestVersionString(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/spf13/cobra"
+)
+
+// errSelfTestFailed is returned by the selftest command when a category returns no rules or fails.
+var errSelfTestFailed = errors.New("self-test failed")
+
+// selfTestResult holds the outcome of querying the rules of one category.
+type selfTestResult struct {
+	Err      error
+	Category string
+	Rules    int
+}
+
+// newSelfTestCommand creates the selftest subcommand, which loads a config as the server
+// would and queries the rules of each category, as a smoke test of a deployment.
+func newSelfTestCommand() *cobra.Command {
+	arg := &args{ConfigTimeout: defaultConfigTimeout}
+
+	cmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "Query the rules of each category of a config",
+		Long:  "Load a config as the server would and query the rules of each category; exits with an error status if a category returns no rules or fails",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := initConfig(arg)
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			// Failures past this point are about the deployment, usage would only bury them
+			cmd.SilenceUsage = true
+
+			svc, err := newCoreService(cmd.Context(), cfg)
+			if err != nil {
+				return err
+			}
+
+			categories, err := selfTestCategories(cmd.Context(), cfg, svc)
+			if err != nil {
+				return err
+			}
+
+			if len(categories) == 0 {
+				return fmt.Errorf("%w: no categories to query", errSelfTestFailed)
+			}
+
+			results := runSelfTest(cmd.Context(), svc, categories)
+
+			failed, err := writeSelfTest(cmd.OutOrStdout(), results)
+			if err != nil {
+				return err
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%w: %d of %d categories", errSelfTestFailed, failed, len(results))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&arg.ConfigPath, "config", "", "config file path or http(s) URL")
+	cmd.Flags().DurationVar(&arg.ConfigTimeout, "config-timeout", defaultConfigTimeout, "timeout for fetching remote config")
+	cmd.Flags().BoolVar(&arg.NoDefaults, "no-defaults", false, "query no rules instead of the default rules when none are configured")
+
+	_ = cmd.MarkFlagRequired("config") // flag is defined above
+
+	return cmd
+}
+
+// selfTestCategories returns the categories to query: the declared categories of the config
+// if any, so that categories without rules are reported, or the categories of the repository rules.
+// Returns error if the repository stats cannot be read.
+func selfTestCategories(ctx context.Context, cfg *Config, svc *core.Service) ([]string, error) {
+	if len(cfg.Categories) > 0 {
+		categories := make([]string, 0, len(cfg.Categories))
+		for _, category := range cfg.Categories {
+			categories = append(categories, category.Name)
+		}
+
+		return categories, nil
+	}
+
+	stats, err := svc.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get stats: %w", err)
+	}
+
+	return slices.Sorted(maps.Keys(stats.RulesPerCategory)), nil
+}
+
+// runSelfTest queries the rules of each category with the default filter of the codestyle tool.
+func runSelfTest(ctx context.Context, svc *core.Service, categories []string) []selfTestResult {
+	results := make([]selfTestResult, 0, len(categories))
+
+	for _, category := range categories {
+		rules, err := svc.GetCodeStyle(ctx, []string{category}, core.Filter{})
+		results = append(results, selfTestResult{Category: category, Rules: len(rules), Err: err})
+	}
+
+	return results
+}
+
+// writeSelfTest writes one line per category and a summary, and returns the number of
+// categories that returned no rules or failed.
+func writeSelfTest(w io.Writer, results []selfTestResult) (int, error) {
+	var sb strings.Builder
+
+	failed := 0
+
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			failed++
+
+			fmt.Fprintf(&sb, "fail: %s: %v\n", result.Category, result.Err)
+		case result.Rules == 0:
+			failed++
+
+			fmt.Fprintf(&sb, "fail: %s: no rules\n", result.Category)
+		default:
+			fmt.Fprintf(&sb, "ok: %s: %d rules\n", result.Category, result.Rules)
+		}
+	}
+
+	fmt.Fprintf(&sb, "%d categories, %d failed\n", len(results), failed)
+
+	_, err := io.WriteString(w, sb.String())
+
+	return failed, err
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTestCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeConfig := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+
+	healthy := writeConfig("healthy.yaml", `
+rules:
+  - name: "table_tests"
+    category: "testing"
+  - name: "early_return"
+    category: "code"
+  - name: "errgroup"
+    category: "code"
+`)
+
+	declared := writeConfig("declared.yaml", `
+categories:
+  - name: "code"
+  - name: "testing"
+rules:
+  - name: "early_return"
+    category: "code"
+  - name: "old_tests"
+    category: "testing"
+    deprecated: true
+`)
+
+	empty := writeConfig("empty.yaml", "rules: []\n")
+
+	tests := []struct {
+		wantErr error
+		name    string
+		config  string
+		want    string
+		flags   []string
+	}{
+		{
+			name:   "categories of the rules",
+			config: healthy,
+			want:   "ok: code: 2 rules\nok: testing: 1 rules\n2 categories, 0 failed\n",
+		},
+		{
+			name:    "declared category without served rules",
+			config:  declared,
+			want:    "ok: code: 1 rules\nfail: testing: no rules\n2 categories, 1 failed\n",
+			wantErr: errSelfTestFailed,
+		},
+		{
+			name:   "default rules",
+			config: empty,
+			want:   "ok: code: 5 rules\nok: documentation: 2 rules\nok: testing: 2 rules\n3 categories, 0 failed\n",
+		},
+		{
+			name:    "no categories",
+			config:  empty,
+			flags:   []string{"--no-defaults"},
+			wantErr: errSelfTestFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := InitCommands("test", "1.0.0")
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(append([]string{"selftest", "--config", tt.config}, tt.flags...))
+
+			err = cmd.Execute()
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestWriteSelfTest(t *testing.T) {
+	var out bytes.Buffer
+
+	failed, err := writeSelfTest(&out, []selfTestResult{
+		{Category: "code", Rules: 2},
+		{Category: "testing", Err: assert.AnError},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, "ok: code: 2 rules\nfail: testing: "+assert.AnError.Error()+"\n2 categories, 1 failed\n", out.String())
+}
@@ -3,26 +3,134 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/ksysoev/mcp-go-tools/pkg/api"
 	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/ksysoev/mcp-go-tools/pkg/repo"
 	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
 )
 
 // runStart initializes and runs the MCP code tools server with the provided configuration.
 // It sets up the component chain in the following order:
-// 1. Static repository for rule storage
-// 2. Core service for business logic
+// 1. Rule repository, see newRepo, whose diagnostics are logged, see logDiagnostics,
+// and which is primed with --warmup, see warmup
+// 2. Core service for business logic, auditing queries if api.auditLog is set and
+// falling back along api.languageFallback for languages without rules
 // 3. MCP API service for handling tool requests
 //
+// For remote configs with a refresh interval, or local configs loaded with
+// --config-watch or --config-poll-interval, a background watcher swaps the repository whenever the rules change.
+//
+// The info identifies the server to MCP clients.
 // The function runs until the context is cancelled or an error occurs.
 // Returns error if any component initialization fails or the server encounters an error.
-func runStart(ctx context.Context, cfg *Config) error {
-	staticRepo := static.New(&cfg.Rules)
+func runStart(ctx context.Context, cfg *Config, info api.ServerInfo) error {
+	toolHandler, err := newCoreService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	logDiagnostics(ctx, toolHandler)
+
+	if cfg.warmup {
+		warmup(ctx, toolHandler)
+	}
+
+	toolHandler.SetLanguageFallback(cfg.API.LanguageFallback)
+
+	if cfg.API.AuditLog != "" {
+		audit, err := core.OpenAuditLog(cfg.API.AuditLog)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := audit.Close(); err != nil {
+				slog.Error("Failed to close audit log", slog.Any("error", err))
+			}
+		}()
 
-	toolHandler := core.New(staticRepo)
+		toolHandler.SetAuditLog(audit)
+	}
 
-	mcpAPI := api.New(&cfg.API, toolHandler)
+	if cfg.remote != nil && cfg.remote.interval > 0 {
+		go cfg.remote.watch(ctx, toolHandler)
+	}
+
+	if cfg.watcher != nil {
+		cfg.watcher.watch(ctx, toolHandler)
+	}
+
+	mcpAPI := api.New(&cfg.API, toolHandler, info)
 
 	return mcpAPI.Run(ctx)
 }
+
+// newCoreService creates the core service serving the rules of the repository selected by cfg.
+// Returns error if the repository cannot be created.
+func newCoreService(ctx context.Context, cfg *Config) (*core.Service, error) {
+	resource, err := newRepo(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository: %w", err)
+	}
+
+	return core.New(resource), nil
+}
+
+// logDiagnostics logs the diagnostics of the repository of svc at info level, so that
+// the state a server started with can be found in its logs.
+func logDiagnostics(ctx context.Context, svc *core.Service) {
+	diag := svc.Diagnose(ctx)
+
+	slog.Info("Repository diagnostics",
+		slog.String("type", diag.Type),
+		slog.Int("rules", diag.Rules),
+		slog.Any("categories", diag.Categories),
+		slog.Any("problems", diag.Problems),
+	)
+}
+
+// warmup primes the repository of svc so that the first request does not pay for its lazy
+// initialization, and logs how long it took. Failures are only logged, as the repository
+// then initializes on the first request instead.
+func warmup(ctx context.Context, svc *core.Service) {
+	start := time.Now()
+
+	if err := svc.Warmup(ctx); err != nil {
+		slog.Warn("Repository warm-up failed", slog.Duration("duration", time.Since(start)), slog.Any("error", err))
+		return
+	}
+
+	slog.Info("Repository warmed up", slog.Duration("duration", time.Since(start)))
+}
+
+// newRepo creates the rule repository selected by the repository.type config key.
+// The built-in static repository, used when the type is empty, serves the rules
+// from the config; other types are created by the factories registered with repo.Register
+// from the raw repository settings.
+// When the type is empty and no rules are configured, the embedded default rules are
+// served unless disabled with --no-defaults or repository.requireRules is set, which
+// fails with static.ErrNoRules instead.
+// Cancelling ctx aborts the creation of registered repositories.
+// Returns error if the type is unknown or the repository cannot be created.
+func newRepo(ctx context.Context, cfg *Config) (core.ResourceRepo, error) {
+	if cfg.repoType == "" && len(cfg.Rules) == 0 && !cfg.noDefaults && !cfg.Repository.RequireRules {
+		rules, err := defaultRules()
+		if err != nil {
+			return nil, err
+		}
+
+		slog.Info("No rules configured, serving the default rules", slog.Int("rules", len(rules)))
+
+		return static.New(&rules, &cfg.Repository)
+	}
+
+	if cfg.repoType == "" || cfg.repoType == repo.TypeStatic {
+		return static.New(&cfg.Rules, &cfg.Repository)
+	}
+
+	return repo.New(ctx, cfg.repoType, cfg.repoSettings)
+}
@@ -1,13 +1,22 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/ksysoev/mcp-go-tools/pkg/api"
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/ksysoev/mcp-go-tools/pkg/repo"
 	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStartServerWithError(t *testing.T) {
@@ -34,11 +43,22 @@ func TestStartServerWithError(t *testing.T) {
 		{
 			name: "empty rules",
 			config: &Config{
-				API:   api.Config{},
-				Rules: static.Config{},
+				API:        api.Config{},
+				Rules:      static.Config{},
+				noDefaults: true,
 			},
 			wantError: false,
 		},
+		{
+			name: "empty rules with require rules",
+			config: &Config{
+				API:        api.Config{},
+				Rules:      static.Config{},
+				Repository: static.Options{RequireRules: true},
+			},
+			runErr:    static.ErrNoRules,
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -50,7 +70,7 @@ func TestStartServerWithError(t *testing.T) {
 			// Start the server
 			errCh := make(chan error, 1)
 			go func() {
-				errCh <- runStart(ctx, tt.config)
+				errCh <- runStart(ctx, tt.config, api.ServerInfo{})
 			}()
 
 			// Wait for either error or timeout
@@ -89,7 +109,7 @@ func TestStartServerContextCancellation(t *testing.T) {
 	// Start server
 	errCh := make(chan error)
 	go func() {
-		errCh <- runStart(ctx, config)
+		errCh <- runStart(ctx, config, api.ServerInfo{})
 	}()
 
 	// Wait a bit to ensure server is running
@@ -106,3 +126,277 @@ func TestStartServerContextCancellation(t *testing.T) {
 		assert.Fail(t, "server did not stop after context cancellation")
 	}
 }
+
+func TestNewRepo(t *testing.T) {
+	custom := core.NewMockResourceRepo(t)
+
+	repo.Register("cmd-test", func(_ context.Context, cfg map[string]any) (core.ResourceRepo, error) {
+		if cfg["dsn"] != "postgres://rules" {
+			return nil, fmt.Errorf("unexpected settings: %v", cfg)
+		}
+
+		return custom, nil
+	})
+
+	tests := []struct {
+		wantRepo core.ResourceRepo
+		wantErr  error
+		name     string
+		config   string
+	}{
+		{
+			name:   "static by default",
+			config: "rules:\n  - name: rule\n    category: code\n",
+		},
+		{
+			name:   "explicit static",
+			config: "repository:\n  type: static\nrules:\n  - name: rule\n    category: code\n",
+		},
+		{
+			name:     "registered type",
+			config:   "repository:\n  type: cmd-test\n  dsn: postgres://rules\n",
+			wantRepo: custom,
+		},
+		{
+			name:    "unknown type",
+			config:  "repository:\n  type: unknown\n",
+			wantErr: repo.ErrUnknownType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.config), 0o600))
+
+			cfg, err := initConfig(&args{ConfigPath: configPath})
+			require.NoError(t, err)
+
+			resource, err := newRepo(context.Background(), cfg)
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantRepo != nil:
+				require.NoError(t, err)
+				assert.Same(t, tt.wantRepo, resource)
+			default:
+				require.NoError(t, err)
+				assert.IsType(t, &static.Repository{}, resource)
+			}
+		})
+	}
+}
+
+func TestNewRepoTrimExamples(t *testing.T) {
+	// The block scalar keeps trailing spaces, a tab and blank lines with the "+" indicator
+	rules := "rules:\n" +
+		"  - name: rule\n" +
+		"    category: code\n" +
+		"    examples:\n" +
+		"      - description: messy\n" +
+		"        code: |+\n" +
+		"          func f() {   \n" +
+		"              if ok {\t\n" +
+		"          \n" +
+		"                  return\n" +
+		"              }\n" +
+		"          }  \n" +
+		"          \n" +
+		"\n"
+
+	tests := []struct {
+		name     string
+		settings string
+		want     string
+	}{
+		{
+			name: "trimmed by default",
+			want: "func f() {\n    if ok {\n\n        return\n    }\n}\n",
+		},
+		{
+			name:     "trimming disabled",
+			settings: "repository:\n  trimExamples: false\n",
+			want:     "func f() {   \n    if ok {\t\n\n        return\n    }\n}  \n\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.settings+rules), 0o600))
+
+			cfg, err := initConfig(&args{ConfigPath: configPath})
+			require.NoError(t, err)
+
+			resource, err := newRepo(context.Background(), cfg)
+			require.NoError(t, err)
+
+			got, err := resource.GetByNames(context.Background(), []string{"rule"})
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0].Examples[0].Code)
+		})
+	}
+}
+
+func TestRunStartCancelledDuringRepoInit(t *testing.T) {
+	started := make(chan struct{})
+
+	repo.Register("cmd-slow", func(ctx context.Context, _ map[string]any) (core.ResourceRepo, error) {
+		close(started)
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	err := runStart(ctx, &Config{repoType: "cmd-slow"}, api.ServerInfo{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewRepoDefaultRules(t *testing.T) {
+	defaults, err := defaultRules()
+	require.NoError(t, err)
+
+	tests := []struct {
+		wantErr   error
+		name      string
+		config    string
+		wantRules int
+		noConfig  bool
+		noDefault bool
+	}{
+		{
+			name:      "no config",
+			noConfig:  true,
+			wantRules: len(defaults),
+		},
+		{
+			name:      "no config without defaults",
+			noConfig:  true,
+			noDefault: true,
+		},
+		{
+			name:      "config without rules",
+			config:    "api:\n  prettyJSON: true\n",
+			wantRules: len(defaults),
+		},
+		{
+			name:      "configured rules",
+			config:    "rules:\n  - name: rule\n    category: code\n",
+			wantRules: 1,
+		},
+		{
+			name:   "explicit static without rules",
+			config: "repository:\n  type: static\n",
+		},
+		{
+			name:    "config without rules requiring rules",
+			config:  "repository:\n  requireRules: true\n",
+			wantErr: static.ErrNoRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arg := &args{NoDefaults: tt.noDefault}
+
+			if !tt.noConfig {
+				arg.ConfigPath = filepath.Join(t.TempDir(), "config.yaml")
+				require.NoError(t, os.WriteFile(arg.ConfigPath, []byte(tt.config), 0o600))
+			}
+
+			cfg, err := initConfig(arg)
+			require.NoError(t, err)
+
+			resource, err := newRepo(context.Background(), cfg)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			count, err := resource.Count(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRules, count)
+		})
+	}
+}
+
+func TestLogDiagnostics(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	var buf bytes.Buffer
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	resource, err := static.New(&static.Config{
+		{Name: "rule1", Category: "testing"},
+		{Name: "rule2", Category: "code"},
+	}, &static.Options{})
+	require.NoError(t, err)
+
+	logDiagnostics(context.Background(), core.New(resource))
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "Repository diagnostics", entry["msg"])
+	assert.Equal(t, "static", entry["type"])
+	assert.InDelta(t, 2, entry["rules"], 0)
+	assert.Equal(t, []any{"code", "testing"}, entry["categories"])
+	assert.Nil(t, entry["problems"])
+}
+
+func TestWarmup(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	resource, err := static.New(&static.Config{{Name: "rule1", Category: "testing"}}, &static.Options{})
+	require.NoError(t, err)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		ctx     context.Context
+		name    string
+		level   string
+		msg     string
+		wantErr bool
+	}{
+		{name: "success", ctx: context.Background(), level: "INFO", msg: "Repository warmed up"},
+		{name: "failure", ctx: cancelled, level: "WARN", msg: "Repository warm-up failed", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+			warmup(tt.ctx, core.New(resource))
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+			assert.Equal(t, tt.level, entry["level"])
+			assert.Equal(t, tt.msg, entry["msg"])
+			assert.Contains(t, entry, "duration")
+
+			if tt.wantErr {
+				assert.Equal(t, context.Canceled.Error(), entry["error"])
+			}
+		})
+	}
+}
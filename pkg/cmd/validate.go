@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
+	"github.com/spf13/cobra"
+)
+
+// errValidationFailed is returned by the validate command when the config has errors.
+var errValidationFailed = errors.New("config validation failed")
+
+// validateArgs holds the command-line arguments of the validate command.
+type validateArgs struct {
+	Config string
+	Format string
+}
+
+// validationSummary counts the rules checked and the problems found.
+type validationSummary struct {
+	Rules    int `json:"rules"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+}
+
+// validationReport holds the result of validating the rules of a config.
+type validationReport struct {
+	Errors   []static.Problem  `json:"errors"`
+	Warnings []static.Problem  `json:"warnings"`
+	Summary  validationSummary `json:"summary"`
+}
+
+// newValidateCommand creates the validate subcommand, which checks the rules of a
+// config without starting the server.
+func newValidateCommand() *cobra.Command {
+	arg := &validateArgs{}
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the rules of a config",
+		Long:  "Report the errors that prevent the rules of a config from loading and the lint warnings about them; exits with an error status if there are errors",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if arg.Format != outputFormatText && arg.Format != outputFormatJSON {
+				return fmt.Errorf("unsupported validate format %q: supported formats are %s, %s", arg.Format, outputFormatText, outputFormatJSON)
+			}
+
+			cfg, err := initConfig(&args{ConfigPath: arg.Config, ConfigTimeout: defaultConfigTimeout})
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			report := validateRules(cfg.Rules, cfg.Repository)
+
+			if arg.Format == outputFormatJSON {
+				err = json.NewEncoder(cmd.OutOrStdout()).Encode(report)
+			} else {
+				err = report.writeText(cmd.OutOrStdout())
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if report.Summary.Errors > 0 {
+				// The report already describes the problems, usage would only bury it
+				cmd.SilenceUsage = true
+
+				return fmt.Errorf("%w: %d errors", errValidationFailed, report.Summary.Errors)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&arg.Config, "config", "", "config file path or http(s) URL")
+	cmd.Flags().StringVar(&arg.Format, "format", outputFormatText, "output format (text, json)")
+
+	_ = cmd.MarkFlagRequired("config") // flag is defined above
+
+	return cmd
+}
+
+// validateRules checks rules with the repository options they are loaded with.
+func validateRules(rules static.Config, opts static.Options) validationReport {
+	errs, warnings := static.Validate(&rules, &opts)
+
+	report := validationReport{
+		Errors:   []static.Problem{},
+		Warnings: []static.Problem{},
+	}
+
+	report.Errors = append(report.Errors, errs...)
+	report.Warnings = append(report.Warnings, warnings...)
+	report.Summary = validationSummary{
+		Rules:    len(rules),
+		Errors:   len(errs),
+		Warnings: len(warnings),
+	}
+
+	return report
+}
+
+// writeText writes a human-readable report of the validation.
+func (r validationReport) writeText(w io.Writer) error {
+	var sb strings.Builder
+
+	writeProblems(&sb, "error", r.Errors)
+	writeProblems(&sb, "warning", r.Warnings)
+
+	fmt.Fprintf(&sb, "%d rules, %d errors, %d warnings\n", r.Summary.Rules, r.Summary.Errors, r.Summary.Warnings)
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// writeProblems writes one line per problem, prefixed with its level and rule name.
+func writeProblems(sb *strings.Builder, level string, problems []static.Problem) {
+	for _, problem := range problems {
+		if problem.Rule == "" {
+			fmt.Fprintf(sb, "%s: %s\n", level, problem.Message)
+		} else {
+			fmt.Fprintf(sb, "%s: %s: %s\n", level, problem.Rule, problem.Message)
+		}
+	}
+}
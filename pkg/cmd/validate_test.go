@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	validPath := filepath.Join(tmpDir, "valid.yaml")
+	require.NoError(t, os.WriteFile(validPath, []byte(`
+repository:
+  lint:
+    maxExamples: 1
+rules:
+  - name: "table_tests"
+    category: "testing"
+    examples:
+      - code: "a"
+      - code: "b"
+`), 0o600))
+
+	invalidPath := filepath.Join(tmpDir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalidPath, []byte(`
+rules:
+  - name: "early_return"
+    category: "code"
+    severity: "critical"
+    conflictsWith: ["missing"]
+`), 0o600))
+
+	tests := []struct {
+		name      string
+		want      string
+		args      []string
+		wantError bool
+		wantJSON  bool
+	}{
+		{
+			name: "text output with warnings",
+			args: []string{"validate", "--config", validPath},
+			want: "warning: table_tests: rule has 2 examples, more than 1\n1 rules, 0 errors, 1 warnings\n",
+		},
+		{
+			name:     "json output with warnings",
+			args:     []string{"validate", "--config", validPath, "--format", "json"},
+			want:     `{"errors":[],"warnings":[{"rule":"table_tests","message":"rule has 2 examples, more than 1"}],"summary":{"rules":1,"errors":0,"warnings":1}}`,
+			wantJSON: true,
+		},
+		{
+			name: "json output with errors",
+			args: []string{"validate", "--config", invalidPath, "--format", "json"},
+			want: `{"errors":[` +
+				`{"rule":"early_return","message":"invalid severity \"critical\" in rule \"early_return\""},` +
+				`{"rule":"early_return","message":"conflict with unknown rule \"missing\" in rule \"early_return\""}` +
+				`],"warnings":[],"summary":{"rules":1,"errors":2,"warnings":0}}`,
+			wantError: true,
+			wantJSON:  true,
+		},
+		{
+			name:      "unsupported format",
+			args:      []string{"validate", "--config", validPath, "--format", "xml"},
+			wantError: true,
+		},
+		{
+			name:      "missing config flag",
+			args:      []string{"validate"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := InitCommands("test", "1.0.0")
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(tt.args)
+
+			err = cmd.Execute()
+
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			switch {
+			case tt.wantJSON:
+				assert.JSONEq(t, tt.want, out.String())
+			case tt.want != "":
+				assert.Equal(t, tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestValidateCommand_ErrorStatus(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+rules:
+  - name: "bad"
+    category: "code"
+    updatedAt: "yesterday"
+`), 0o600))
+
+	cmd, err := InitCommands("test", "1.0.0")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"validate", "--config", configPath})
+
+	err = cmd.Execute()
+
+	assert.ErrorIs(t, err, errValidationFailed)
+	assert.True(t, strings.HasPrefix(out.String(), "error: bad: invalid updatedAt"), out.String())
+	assert.True(t, strings.HasSuffix(out.String(), "1 rules, 1 errors, 0 warnings\n"), out.String())
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// fileWatcher reloads the rules when the local config file changes.
+// Changes are detected with file system events, by polling the file modification
+// time, or both; polling covers file systems where events are unreliable, such as
+// NFS mounts and Docker volumes.
+type fileWatcher struct {
+	v    *viper.Viper
+	path string
+	// pollInterval is the interval between modification time checks, zero disables polling
+	pollInterval time.Duration
+	// events enables watching for file system events
+	events bool
+	// noDefaults is passed on to every reloaded config, see Config.noDefaults
+	noDefaults bool
+}
+
+// watch starts watching the config file and swaps the repository of target
+// whenever the file changes. Reload errors are logged and the current repository
+// is kept. Only rules and repository settings are reloaded.
+// The event watcher runs for the lifetime of the process, polling stops when ctx is cancelled.
+func (fw *fileWatcher) watch(ctx context.Context, target repoSetter) {
+	if fw.events {
+		fw.v.OnConfigChange(func(fsnotify.Event) {
+			// viper re-reads only the first document of multi-document YAML files
+			if err := mergeConfigFileDocuments(fw.v); err != nil {
+				slog.Error("Failed to read config file", slog.String("path", fw.path), slog.Any("error", err))
+				return
+			}
+
+			fw.reload(ctx, fw.v, target)
+		})
+
+		fw.v.WatchConfig()
+
+		slog.Info("Watching config file for changes", slog.String("path", fw.path))
+	}
+
+	if fw.pollInterval > 0 {
+		go fw.poll(ctx, target)
+
+		slog.Info("Polling config file for changes", slog.String("path", fw.path), slog.Duration("interval", fw.pollInterval))
+	}
+}
+
+// poll checks the config file every poll interval, see pollTicks.
+// It blocks until ctx is cancelled.
+func (fw *fileWatcher) poll(ctx context.Context, target repoSetter) {
+	ticker := time.NewTicker(fw.pollInterval)
+	defer ticker.Stop()
+
+	fw.pollTicks(ctx, ticker.C, target)
+}
+
+// pollTicks checks the modification time and size of the config file on every tick
+// and reloads it when either changed. It blocks until ctx is cancelled.
+func (fw *fileWatcher) pollTicks(ctx context.Context, ticks <-chan time.Time, target repoSetter) {
+	last, _ := os.Stat(fw.path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticks:
+			info, err := os.Stat(fw.path)
+			if err != nil {
+				slog.Error("Failed to check config file", slog.String("path", fw.path), slog.Any("error", err))
+				continue
+			}
+
+			if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+				continue
+			}
+
+			last = info
+
+			// A separate viper instance keeps polling independent of the event watcher
+			v := viper.NewWithOptions()
+			v.SetConfigFile(fw.path)
+
+			if err := readConfigFile(v); err != nil {
+				slog.Error("Failed to read config file", slog.String("path", fw.path), slog.Any("error", err))
+				continue
+			}
+
+			fw.reload(ctx, v, target)
+		}
+	}
+}
+
+// reload decodes the config loaded into v and swaps the repository of target.
+func (fw *fileWatcher) reload(ctx context.Context, v *viper.Viper, target repoSetter) {
+	cfg, err := unmarshalConfig(v)
+	if err != nil {
+		slog.Error("Failed to reload config file", slog.String("path", fw.path), slog.Any("error", err))
+		return
+	}
+
+	cfg.noDefaults = fw.noDefaults
+
+	resource, err := newRepo(ctx, cfg)
+	if err != nil {
+		slog.Error("Failed to create repository from config file", slog.String("path", fw.path), slog.Any("error", err))
+		return
+	}
+
+	target.SetRepo(resource)
+
+	slog.Info("Config file reloaded", slog.String("path", fw.path), slog.Int("rules", len(cfg.Rules)))
+}
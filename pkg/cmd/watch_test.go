@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRulesFile(t *testing.T, path, ruleName string) {
+	t.Helper()
+
+	content := "rules:\n  - name: " + ruleName + "\n    category: code\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestInitConfigWatchFlag(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeRulesFile(t, configPath, "rule_v1")
+
+	tests := []struct {
+		name        string
+		configPoll  time.Duration
+		configWatch bool
+	}{
+		{name: "watch disabled"},
+		{name: "watch enabled", configWatch: true},
+		{name: "polling enabled", configPoll: time.Second},
+		{name: "watch and polling enabled", configWatch: true, configPoll: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := initConfig(&args{ConfigPath: configPath, ConfigWatch: tt.configWatch, ConfigPoll: tt.configPoll})
+			require.NoError(t, err)
+
+			if !tt.configWatch && tt.configPoll == 0 {
+				assert.Nil(t, cfg.watcher)
+				return
+			}
+
+			require.NotNil(t, cfg.watcher)
+			assert.Equal(t, configPath, cfg.watcher.path)
+			assert.Equal(t, tt.configWatch, cfg.watcher.events)
+			assert.Equal(t, tt.configPoll, cfg.watcher.pollInterval)
+		})
+	}
+}
+
+func TestFileWatcherWatch(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeRulesFile(t, configPath, "rule_v1")
+
+	cfg, err := initConfig(&args{ConfigPath: configPath, ConfigWatch: true})
+	require.NoError(t, err)
+
+	setter := &fakeRepoSetter{}
+	cfg.watcher.watch(context.Background(), setter)
+
+	writeRulesFile(t, configPath, "rule_v2")
+
+	require.Eventually(t, func() bool { return setter.count() > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	setter.mu.Lock()
+	repo := setter.repos[len(setter.repos)-1]
+	setter.mu.Unlock()
+
+	rules, err := repo.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "rule_v2", rules[0].Name)
+}
+
+// chanRepoSetter sends every repository it is given to the channel.
+type chanRepoSetter chan core.ResourceRepo
+
+func (c chanRepoSetter) SetRepo(resource core.ResourceRepo) {
+	c <- resource
+}
+
+func TestFileWatcherPoll(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeRulesFile(t, configPath, "rule_v1")
+
+	cfg, err := initConfig(&args{ConfigPath: configPath, ConfigPoll: time.Hour})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	reloads := make(chanRepoSetter, 2)
+	ticks := make(chan time.Time)
+
+	go func() {
+		defer close(done)
+		cfg.watcher.pollTicks(ctx, ticks, reloads)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// ticks is unbuffered, so a second tick is only received once the first one is handled
+	tick := func() {
+		ticks <- time.Now()
+		ticks <- time.Now()
+	}
+
+	// Unchanged files are not reloaded
+	tick()
+	assert.Empty(t, reloads)
+
+	writeRulesFile(t, configPath, "rule_v2")
+
+	// Bump the modification time, as the new contents have the same size
+	modTime := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(configPath, modTime, modTime))
+
+	tick()
+	require.Eventually(t, func() bool { return len(reloads) > 0 }, 5*time.Second, time.Millisecond)
+
+	repo := <-reloads
+
+	rules, err := repo.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "rule_v2", rules[0].Name)
+
+	tick()
+	assert.Empty(t, reloads, "the file should be reloaded once per change")
+}
+
+func TestFileWatcherReloadInvalidRules(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "rules:\n  - name: rule\n    category: code\n    severity: critical\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+
+	// Severity is validated by the repository, so the config itself loads fine
+	cfg, err := initConfig(&args{ConfigPath: configPath, ConfigWatch: true})
+	require.NoError(t, err)
+
+	setter := &fakeRepoSetter{}
+	cfg.watcher.reload(context.Background(), cfg.watcher.v, setter)
+
+	assert.Equal(t, 0, setter.count())
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Audited query kinds, recorded as AuditRecord.Query.
+const (
+	AuditQueryCodeStyle = "codestyle"
+	AuditQueryByNames   = "getrules"
+	AuditQueryRecommend = "recommend"
+)
+
+// AuditRecord describes one rule query in the audit log.
+type AuditRecord struct {
+	Time         time.Time `json:"time"`
+	Query        string    `json:"query"`
+	Client       string    `json:"client,omitempty"`
+	CodeContains string    `json:"code_contains,omitempty"`
+	Categories   []string  `json:"categories,omitempty"`
+	Names        []string  `json:"names,omitempty"`
+	Results      int       `json:"results"`
+}
+
+// AuditLog is an append-only sink writing one JSON line per rule query, kept apart
+// from the diagnostic logger. Writes are buffered; Close flushes them.
+// It is safe for concurrent use.
+type AuditLog struct {
+	closer io.Closer
+	buf    *bufio.Writer
+	enc    *json.Encoder
+	mu     sync.Mutex
+}
+
+// NewAuditLog creates an audit log writing to w. If w is an io.Closer, Close closes it.
+func NewAuditLog(w io.Writer) *AuditLog {
+	buf := bufio.NewWriter(w)
+	a := &AuditLog{
+		buf: buf,
+		enc: json.NewEncoder(buf),
+	}
+
+	if closer, ok := w.(io.Closer); ok {
+		a.closer = closer
+	}
+
+	return a
+}
+
+// OpenAuditLog opens the audit log file at path for appending, creating it if needed.
+// Returns error if the file cannot be opened.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	return NewAuditLog(file), nil
+}
+
+// Record appends rec to the log. Write failures are reported to the diagnostic logger,
+// as a query must not fail because it could not be audited.
+func (a *AuditLog) Record(rec AuditRecord) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.enc.Encode(rec); err != nil {
+		slog.Error("Failed to write audit record", slog.Any("error", err))
+	}
+}
+
+// Close flushes buffered records and closes the underlying writer, if it is an io.Closer.
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	err := a.buf.Flush()
+
+	if a.closer != nil {
+		if closeErr := a.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// SetAuditLog sets the sink recording every rule query; nil disables auditing.
+// The caller remains responsible for closing the log.
+func (s *Service) SetAuditLog(audit *AuditLog) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.audit = audit
+}
+
+// recordQuery completes rec with the time and client of the query and appends it to
+// the audit log, if any.
+func (s *Service) recordQuery(ctx context.Context, rec AuditRecord, results int) {
+	s.mu.RLock()
+	audit := s.audit
+	s.mu.RUnlock()
+
+	if audit == nil {
+		return
+	}
+
+	rec.Time = time.Now().UTC()
+	rec.Client = ClientIDFromContext(ctx)
+	rec.Results = results
+
+	audit.Record(rec)
+}
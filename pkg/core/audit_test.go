@@ -0,0 +1,132 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeAuditRecords(t *testing.T, data string) []AuditRecord {
+	t.Helper()
+
+	var records []AuditRecord
+
+	for line := range strings.Lines(data) {
+		var rec AuditRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+
+		records = append(records, rec)
+	}
+
+	return records
+}
+
+func TestService_recordQuery(t *testing.T) {
+	ctx := WithClientID(context.Background(), "cursor")
+
+	repoRules := []Rule{
+		{Name: "error_wrapping", Category: "code", Description: "Wrap errors with fmt.Errorf"},
+		{Name: "table_tests", Category: "testing"},
+	}
+
+	filter := Filter{CodeContains: "errorf"}
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().GetCodeStyle(ctx, []string{"code"}, filter).Return(repoRules[:1], nil)
+	mockRepo.EXPECT().GetByNames(ctx, []string{"table_tests", "unknown"}).Return(repoRules[1:], nil)
+
+	var out bytes.Buffer
+
+	audit := NewAuditLog(&out)
+
+	svc := New(mockRepo)
+	svc.SetAuditLog(audit)
+
+	rules, err := svc.GetCodeStyle(ctx, []string{"code"}, filter)
+	require.NoError(t, err)
+
+	svc.RecordCodeStyle(ctx, []string{"code"}, filter, rules)
+
+	found, _, err := svc.GetByNames(ctx, []string{"table_tests", "unknown"})
+	require.NoError(t, err)
+
+	svc.RecordByNames(ctx, []string{"table_tests", "unknown"}, found)
+
+	_, err = svc.Recommend(ctx, "", []string{"testing"}, 0)
+	require.NoError(t, err)
+
+	assert.Empty(t, out.String(), "records must be buffered until Close")
+	require.NoError(t, audit.Close())
+
+	records := decodeAuditRecords(t, out.String())
+	require.Len(t, records, 3)
+
+	for _, rec := range records {
+		assert.Equal(t, "cursor", rec.Client)
+		assert.False(t, rec.Time.IsZero())
+	}
+
+	assert.Equal(t, AuditQueryCodeStyle, records[0].Query)
+	assert.Equal(t, []string{"code"}, records[0].Categories)
+	assert.Equal(t, "errorf", records[0].CodeContains)
+	assert.Equal(t, 1, records[0].Results)
+
+	assert.Equal(t, AuditQueryByNames, records[1].Query)
+	assert.Equal(t, []string{"table_tests", "unknown"}, records[1].Names)
+	assert.Equal(t, 1, records[1].Results)
+
+	assert.Equal(t, AuditQueryRecommend, records[2].Query)
+	assert.Equal(t, []string{"testing"}, records[2].Categories)
+	assert.Zero(t, records[2].Results)
+}
+
+func TestService_recordQuery_NoAuditLog(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().GetCodeStyle(ctx, []string{"code"}, Filter{}).Return([]Rule{{Name: "rule", Category: "code"}}, nil)
+
+	svc := New(mockRepo)
+
+	rules, err := svc.GetCodeStyle(ctx, []string{"code"}, Filter{})
+	require.NoError(t, err)
+	assert.Len(t, rules, 1)
+
+	svc.RecordCodeStyle(ctx, []string{"code"}, Filter{}, rules)
+}
+
+func TestOpenAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	for _, query := range []string{AuditQueryCodeStyle, AuditQueryRecommend} {
+		audit, err := OpenAuditLog(path)
+		require.NoError(t, err)
+
+		audit.Record(AuditRecord{Query: query})
+		require.NoError(t, audit.Close())
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	records := decodeAuditRecords(t, string(data))
+	require.Len(t, records, 2, "reopening the log must append to it")
+	assert.Equal(t, AuditQueryCodeStyle, records[0].Query)
+	assert.Equal(t, AuditQueryRecommend, records[1].Query)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestOpenAuditLog_Error(t *testing.T) {
+	_, err := OpenAuditLog(filepath.Join(t.TempDir(), "missing", "audit.jsonl"))
+	assert.ErrorContains(t, err, "failed to open audit log")
+}
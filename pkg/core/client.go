@@ -0,0 +1,25 @@
+package core
+
+import "context"
+
+// clientIDKey is the context key for the client identifier.
+type clientIDKey struct{}
+
+// WithClientID returns a copy of ctx that carries the identifier of the client
+// issuing the request. Repositories may use it to serve client-specific rule subsets.
+// An empty id leaves ctx unchanged.
+func WithClientID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, clientIDKey{}, id)
+}
+
+// ClientIDFromContext returns the client identifier carried by ctx,
+// or an empty string if there is none.
+func ClientIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(clientIDKey{}).(string)
+
+	return id
+}
@@ -0,0 +1,16 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientID(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Empty(t, ClientIDFromContext(ctx))
+	assert.Equal(t, ctx, WithClientID(ctx, ""))
+	assert.Equal(t, "team-a", ClientIDFromContext(WithClientID(ctx, "team-a")))
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// FingerprintRules returns a SHA-256 hash of rules, hex encoded. Each rule is canonicalized
+// as its JSON encoding, and the encodings are hashed in sorted order, so the fingerprint
+// does not depend on the order of the rules. The order of examples within a rule matters.
+// Returns error if a rule cannot be encoded.
+func FingerprintRules(rules []Rule) (string, error) {
+	encoded := make([]string, 0, len(rules))
+
+	for i := range rules {
+		data, err := json.Marshal(&rules[i])
+		if err != nil {
+			return "", fmt.Errorf("encode rule %q: %w", rules[i].Name, err)
+		}
+
+		encoded = append(encoded, string(data))
+	}
+
+	slices.Sort(encoded)
+
+	hash := sha256.New()
+
+	for _, data := range encoded {
+		// JSON encodings never contain a raw newline, so it separates rules unambiguously
+		hash.Write([]byte(data))
+		hash.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Fingerprint returns the fingerprint of the rules in the current repository,
+// see FingerprintRules.
+// Returns error if the repository access fails.
+func (s *Service) Fingerprint(ctx context.Context) (string, error) {
+	return s.repo().Fingerprint(ctx)
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprintRules(t *testing.T) {
+	rules := []Rule{
+		{Name: "early_return", Category: "code", Description: "Return early", Examples: []Example{{Code: "return nil"}}},
+		{Name: "table_tests", Category: "testing", Description: "Use table tests"},
+	}
+
+	fingerprint, err := FingerprintRules(rules)
+	require.NoError(t, err)
+	assert.Len(t, fingerprint, 64)
+
+	reversed, err := FingerprintRules([]Rule{rules[1], rules[0]})
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, reversed, "fingerprint must not depend on rule order")
+
+	again, err := FingerprintRules(rules)
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, again)
+
+	changed := []Rule{rules[0], rules[1]}
+	changed[1].Description = "Use table-driven tests"
+
+	other, err := FingerprintRules(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint, other)
+
+	subset, err := FingerprintRules(rules[:1])
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint, subset)
+
+	empty, err := FingerprintRules(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", empty)
+}
+
+func TestService_Fingerprint(t *testing.T) {
+	repo := NewMockResourceRepo(t)
+	repo.EXPECT().Fingerprint(mock.Anything).Return("abc", nil).Once()
+	repo.EXPECT().Fingerprint(mock.Anything).Return("", assert.AnError).Once()
+
+	svc := New(repo)
+
+	fingerprint, err := svc.Fingerprint(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "abc", fingerprint)
+
+	_, err = svc.Fingerprint(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+}
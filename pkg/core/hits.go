@@ -0,0 +1,61 @@
+package core
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// recordHits increments the hit counter of every rule in rules by name.
+func (s *Service) recordHits(rules []Rule) {
+	if len(rules) == 0 {
+		return
+	}
+
+	s.hitsMu.Lock()
+	defer s.hitsMu.Unlock()
+
+	if s.hits == nil {
+		s.hits = make(map[string]int)
+	}
+
+	for _, rule := range rules {
+		s.hits[rule.Name]++
+	}
+}
+
+// RuleHits returns how many times each rule was returned by Recommend, or recorded with
+// RecordCodeStyle and RecordByNames, since the service was created or its repository last
+// replaced, keyed by rule name. Rules that were not returned are absent.
+func (s *Service) RuleHits() map[string]int {
+	s.hitsMu.Lock()
+	defer s.hitsMu.Unlock()
+
+	hits := make(map[string]int, len(s.hits))
+	maps.Copy(hits, s.hits)
+
+	return hits
+}
+
+// resetHits clears all rule hit counters.
+func (s *Service) resetHits() {
+	s.hitsMu.Lock()
+	defer s.hitsMu.Unlock()
+
+	s.hits = nil
+}
+
+// sortByPopularity returns a copy of rules sorted by their hit counters, most returned
+// first. The sort is stable, so rules with equal counts keep their order.
+func (s *Service) sortByPopularity(rules []Rule) []Rule {
+	sorted := slices.Clone(rules)
+
+	s.hitsMu.Lock()
+	defer s.hitsMu.Unlock()
+
+	slices.SortStableFunc(sorted, func(a, b Rule) int {
+		return cmp.Compare(s.hits[b.Name], s.hits[a.Name])
+	})
+
+	return sorted
+}
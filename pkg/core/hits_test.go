@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RuleHits(t *testing.T) {
+	ctx := context.Background()
+
+	repoRules := []Rule{
+		{Name: "error_wrapping", Category: "code", Description: "Wrap errors with fmt.Errorf"},
+		{Name: "table_tests", Category: "testing"},
+	}
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().GetCodeStyle(ctx, []string{AllCategories}, Filter{}).Return(repoRules, nil)
+	mockRepo.EXPECT().GetByNames(ctx, []string{"table_tests", "unknown"}).Return(repoRules[1:], nil)
+
+	svc := New(mockRepo)
+	assert.Empty(t, svc.RuleHits())
+
+	rules, err := svc.GetCodeStyle(ctx, []string{AllCategories}, Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, svc.RuleHits(), "GetCodeStyle leaves recording to the caller")
+
+	svc.RecordCodeStyle(ctx, []string{AllCategories}, Filter{}, rules)
+
+	found, _, err := svc.GetByNames(ctx, []string{"table_tests", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"error_wrapping": 1, "table_tests": 1}, svc.RuleHits(), "GetByNames leaves recording to the caller")
+
+	svc.RecordByNames(ctx, []string{"table_tests", "unknown"}, found)
+
+	_, err = svc.Recommend(ctx, `fmt.Errorf("load: %w", err)`, []string{AllCategories}, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"error_wrapping": 2, "table_tests": 2}, svc.RuleHits())
+
+	svc.SetRepo(NewMockResourceRepo(t))
+	assert.Empty(t, svc.RuleHits(), "hits must reset when the repository is replaced")
+}
+
+func TestService_GetCodeStyle_SortPopularity(t *testing.T) {
+	ctx := context.Background()
+	filter := Filter{SortBy: SortPopularity}
+
+	repoRules := []Rule{
+		{Name: "naming", Category: "code"},
+		{Name: "table_tests", Category: "testing"},
+		{Name: "errors", Category: "code"},
+		{Name: "godoc", Category: "documentation"},
+	}
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().GetCodeStyle(ctx, []string{AllCategories}, filter).Return(repoRules, nil)
+	mockRepo.EXPECT().GetByNames(ctx, []string{"table_tests"}).Return(repoRules[1:2], nil)
+	mockRepo.EXPECT().GetByNames(ctx, []string{"godoc"}).Return(repoRules[3:], nil)
+
+	svc := New(mockRepo)
+
+	names := func() []string {
+		rules, err := svc.GetCodeStyle(ctx, []string{AllCategories}, filter)
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(rules))
+		for _, rule := range rules {
+			names = append(names, rule.Name)
+		}
+
+		return names
+	}
+
+	assert.Equal(t, []string{"errors", "naming", "godoc", "table_tests"}, names(), "without hits, ties are in canonical order")
+
+	for _, name := range []string{"table_tests", "table_tests", "godoc"} {
+		rules, _, err := svc.GetByNames(ctx, []string{name})
+		require.NoError(t, err)
+
+		svc.RecordByNames(ctx, []string{name}, rules)
+	}
+
+	assert.Equal(t, []string{"table_tests", "godoc", "errors", "naming"}, names())
+
+	svc.SetRepo(mockRepo)
+	assert.Equal(t, []string{"errors", "naming", "godoc", "table_tests"}, names(), "hits reset when the repository is replaced")
+}
+
+func TestService_RuleHits_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	rule := Rule{Name: "table_tests", Category: "testing"}
+
+	svc := New(NewMockResourceRepo(t))
+
+	var wg sync.WaitGroup
+
+	for range 50 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			svc.RecordByNames(ctx, []string{"table_tests"}, []Rule{rule})
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, map[string]int{"table_tests": 50}, svc.RuleHits())
+}
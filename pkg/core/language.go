@@ -0,0 +1,134 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+)
+
+// languageSignals lists, per language, patterns matched against each trimmed line of
+// example code; every matching line counts as one point for the language.
+var languageSignals = map[string][]*regexp.Regexp{
+	"go": {
+		regexp.MustCompile(`^package \w+$`),
+		regexp.MustCompile(`^func [\w(]`),
+		regexp.MustCompile(`^import (\(|"|\w+ ")`),
+		regexp.MustCompile(`^type \w+ (struct|interface) \{`),
+		regexp.MustCompile(`\w+ := `),
+		regexp.MustCompile(`^if err != nil \{`),
+		regexp.MustCompile(`^defer `),
+	},
+	"python": {
+		regexp.MustCompile(`^(async )?def \w+\(.*\).*:$`),
+		regexp.MustCompile(`^import [\w.]+( as \w+)?$`),
+		regexp.MustCompile(`^from [\w.]+ import `),
+		regexp.MustCompile(`^class \w+(\(.*\))?:$`),
+		regexp.MustCompile(`^(elif .*|else|try|finally|except.*):$`),
+		regexp.MustCompile(`\bself\.`),
+	},
+	"javascript": {
+		regexp.MustCompile(`^(export )?(async )?function\*? \w*\(`),
+		regexp.MustCompile(`^(export )?(const|let|var) \w+ = `),
+		regexp.MustCompile(`\) => `),
+		regexp.MustCompile(`\bconsole\.log\(`),
+		regexp.MustCompile(`\brequire\(['"]`),
+		regexp.MustCompile(`^import .* from ['"]`),
+	},
+	"bash": {
+		regexp.MustCompile(`^#!/(usr/)?bin/(env )?(ba)?sh`),
+		regexp.MustCompile(`^\$ \w`),
+		regexp.MustCompile(`^(echo|export|cd) |^set -\w+`),
+		regexp.MustCompile(`^fi$|^done$|; then$|; do$`),
+	},
+}
+
+// detectLanguage guesses the language of example code from characteristic lines, such as
+// "package" or "func" for Go and "def" or "import" for Python. It returns the language
+// with the most matching lines, or an empty string if no language or several tied.
+func detectLanguage(code string) string {
+	scores := make(map[string]int, len(languageSignals))
+
+	for line := range strings.Lines(code) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		for lang, signals := range languageSignals {
+			for _, signal := range signals {
+				if signal.MatchString(line) {
+					scores[lang]++
+					break
+				}
+			}
+		}
+	}
+
+	best, bestScore, tied := "", 0, false
+
+	for lang, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = lang, score, false
+		case score == bestScore:
+			tied = true
+		}
+	}
+
+	if tied {
+		return ""
+	}
+
+	return best
+}
+
+// SetLanguageFallback sets the languages tried when no rule of a requested language
+// matches, mapping each language to the next one in its chain, e.g. "typescript" to
+// "javascript" and "javascript" to "" for the rules without a language. Languages are
+// compared case-insensitively; nil disables the fallback.
+func (s *Service) SetLanguageFallback(chain map[string]string) {
+	fallback := make(map[string]string, len(chain))
+	for lang, next := range chain {
+		fallback[strings.ToLower(lang)] = strings.ToLower(next)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fallback = fallback
+}
+
+// selectLanguage returns the rules of language. When there are none, the next language
+// of the fallback chain is tried until one has rules, the chain ends or a language repeats;
+// an empty language in the chain selects the rules without a language.
+// An empty requested language keeps all rules.
+func (s *Service) selectLanguage(rules []Rule, language string) []Rule {
+	if language == "" {
+		return rules
+	}
+
+	s.mu.RLock()
+	fallback := s.fallback
+	s.mu.RUnlock()
+
+	lang := strings.ToLower(language)
+	tried := make(map[string]bool)
+
+	for {
+		tried[lang] = true
+
+		selected := make([]Rule, 0, len(rules))
+
+		for _, rule := range rules {
+			if strings.EqualFold(rule.Language, lang) {
+				selected = append(selected, rule)
+			}
+		}
+
+		next, ok := fallback[lang]
+		if len(selected) > 0 || !ok || tried[next] {
+			return selected
+		}
+
+		lang = next
+	}
+}
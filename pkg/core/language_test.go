@@ -0,0 +1,155 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{
+			name: "go package",
+			code: "package main\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n",
+			want: "go",
+		},
+		{
+			name: "go statements",
+			code: "rows, err := db.Query(q)\nif err != nil {\n\treturn err\n}\ndefer rows.Close()\n",
+			want: "go",
+		},
+		{
+			name: "python",
+			code: "import os\nfrom pathlib import Path\n\ndef read(path):\n    return Path(path).read_text()\n",
+			want: "python",
+		},
+		{
+			name: "javascript",
+			code: "const fs = require('fs');\nconst read = (path) => fs.readFileSync(path);\n",
+			want: "javascript",
+		},
+		{
+			name: "bash",
+			code: "#!/bin/bash\nset -e\nif [ -f go.mod ]; then\n  go test ./...\nfi\n",
+			want: "bash",
+		},
+		{
+			name: "plain text",
+			code: "Use short receiver names",
+			want: "",
+		},
+		{
+			name: "tie",
+			code: "package main\ndef main():\n",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectLanguage(tt.code))
+		})
+	}
+}
+
+func TestService_GetCodeStyle_LanguageFallback(t *testing.T) {
+	chain := map[string]string{
+		"TypeScript": "javascript",
+		"javascript": "",
+		"kotlin":     "java",
+		"java":       "kotlin",
+	}
+
+	tests := []struct {
+		name     string
+		language string
+		chain    map[string]string
+		rules    []Rule
+		expected []string
+	}{
+		{
+			name:     "no language keeps all rules",
+			chain:    chain,
+			rules:    []Rule{{Name: "General"}, {Name: "Script", Language: "javascript"}},
+			expected: []string{"General", "Script"},
+		},
+		{
+			name:     "requested language has rules",
+			language: "typescript",
+			chain:    chain,
+			rules:    []Rule{{Name: "Script", Language: "javascript"}, {Name: "Typed", Language: "TypeScript"}},
+			expected: []string{"Typed"},
+		},
+		{
+			name:     "one step",
+			language: "typescript",
+			chain:    chain,
+			rules:    []Rule{{Name: "General"}, {Name: "Script", Language: "javascript"}, {Name: "Snake", Language: "python"}},
+			expected: []string{"Script"},
+		},
+		{
+			name:     "multiple steps down to rules without a language",
+			language: "TypeScript",
+			chain:    chain,
+			rules:    []Rule{{Name: "General"}, {Name: "Snake", Language: "python"}},
+			expected: []string{"General"},
+		},
+		{
+			name:     "chain exhausted",
+			language: "typescript",
+			chain:    chain,
+			rules:    []Rule{{Name: "Snake", Language: "python"}},
+			expected: []string{},
+		},
+		{
+			name:     "language without a chain",
+			language: "rust",
+			chain:    chain,
+			rules:    []Rule{{Name: "General"}},
+			expected: []string{},
+		},
+		{
+			name:     "cycle stops",
+			language: "kotlin",
+			chain:    chain,
+			rules:    []Rule{{Name: "General"}},
+			expected: []string{},
+		},
+		{
+			name:     "fallback disabled",
+			language: "typescript",
+			rules:    []Rule{{Name: "Script", Language: "javascript"}},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			filter := Filter{Language: tt.language}
+
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().GetCodeStyle(mock.Anything, []string{AllCategories}, filter).Return(tt.rules, nil)
+
+			svc := New(mockRepo)
+			svc.SetLanguageFallback(tt.chain)
+
+			rules, err := svc.GetCodeStyle(ctx, []string{AllCategories}, filter)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
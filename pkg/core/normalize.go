@@ -0,0 +1,40 @@
+package core
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeText folds s for keyword matching: it is lower-cased and stripped of
+// diacritics, so that "Résumé", "RESUME" and "resume" all normalize to "resume".
+// Both the searched text and the query should be normalized before comparing them.
+func NormalizeText(s string) string {
+	if isASCII(s) {
+		return strings.ToLower(s)
+	}
+
+	// Decompose characters so that diacritics become separate marks, drop the marks
+	// and recompose what remains. The transformer is stateful, hence created per call.
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		stripped = s
+	}
+
+	return strings.ToLower(stripped)
+}
+
+// isASCII reports whether s holds only ASCII characters, which have no diacritics.
+func isASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "empty", text: "", want: ""},
+		{name: "ascii", text: "ErrGroup.WithContext", want: "errgroup.withcontext"},
+		{name: "diacritics", text: "Résumé Café Naïve", want: "resume cafe naive"},
+		{name: "upper case diacritics", text: "ÉCOLE ÅNGSTRÖM", want: "ecole angstrom"},
+		{name: "non-latin letters kept", text: "Привет Ёлка", want: "привет елка"},
+		{name: "precomposed and combining marks", text: "\u00e9 e\u0301", want: "e e"},
+		{name: "symbols kept", text: "x := 1 // ok™", want: "x := 1 // ok™"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeText(tt.text))
+		})
+	}
+}
+
+func TestService_GetCodeStyle_CodeContainsAccents(t *testing.T) {
+	ctx := context.Background()
+
+	rules := []Rule{{
+		Name:     "greeting",
+		Category: "code",
+		Examples: []Example{
+			{Description: "Plain", Code: `fmt.Println("hello")`},
+			{Description: "Accented", Code: `fmt.Println("Olá, señor")`},
+		},
+	}}
+
+	filter := Filter{MaxExamplesPerRule: 1, CodeContains: "SENOR"}
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().GetCodeStyle(ctx, []string{"code"}, filter).Return(rules, nil)
+
+	got, err := New(mockRepo).GetCodeStyle(ctx, []string{"code"}, filter)
+
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, []Example{rules[0].Examples[1]}, got[0].Examples)
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultRecommendLimit is the number of rules Recommend returns when no limit is given.
+const DefaultRecommendLimit = 5
+
+// minTermLength is the shortest identifier considered when matching a snippet against rules.
+const minTermLength = 3
+
+// identifierPattern matches identifiers in source code and words in prose.
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// stopTerms are identifiers too common across code snippets to indicate relevance.
+var stopTerms = map[string]bool{
+	"and": true, "break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"def": true, "default": true, "defer": true, "else": true, "false": true, "for": true,
+	"func": true, "import": true, "interface": true, "map": true, "nil": true, "none": true,
+	"not": true, "package": true, "range": true, "return": true, "self": true, "string": true,
+	"struct": true, "switch": true, "the": true, "this": true, "true": true, "type": true,
+	"var": true, "with": true,
+}
+
+// Recommend returns up to limit rules relevant to a code snippet from the given categories.
+// Relevance is the number of distinct identifiers of the snippet that also occur in the
+// rule name, description or example code, compared case-insensitively. Rules sharing no
+// identifier with the snippet are omitted, and deprecated rules are never recommended.
+// Rules with equal relevance keep canonical order. A limit of zero or less uses DefaultRecommendLimit.
+// Returns error if the repository access fails.
+func (s *Service) Recommend(ctx context.Context, code string, categories []string, limit int) ([]Rule, error) {
+	if limit <= 0 {
+		limit = DefaultRecommendLimit
+	}
+
+	terms := identifiers(code)
+	if len(terms) == 0 {
+		s.recordQuery(ctx, AuditRecord{Query: AuditQueryRecommend, Categories: categories}, 0)
+		return []Rule{}, nil
+	}
+
+	rules, err := s.repo().GetCodeStyle(ctx, categories, Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		rule  Rule
+		score int
+	}
+
+	matches := make([]match, 0, len(rules))
+
+	for _, rule := range sortRules(withoutDeprecated(rules)) {
+		if score := relevance(terms, rule); score > 0 {
+			matches = append(matches, match{rule: rule, score: score})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b match) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	recommended := make([]Rule, 0, min(limit, len(matches)))
+	for _, m := range matches[:min(limit, len(matches))] {
+		recommended = append(recommended, m.rule)
+	}
+
+	recommended = sortExamples(recommended)
+
+	s.recordHits(recommended)
+	s.recordQuery(ctx, AuditRecord{Query: AuditQueryRecommend, Categories: categories}, len(recommended))
+
+	return recommended, nil
+}
+
+// relevance counts the terms that occur among the identifiers of the rule text.
+func relevance(terms []string, rule Rule) int {
+	text := []string{rule.Name, rule.Description}
+	for _, ex := range rule.Examples {
+		text = append(text, ex.Code)
+	}
+
+	ruleTerms := identifiers(strings.Join(text, "\n"))
+
+	score := 0
+
+	for _, term := range terms {
+		if _, ok := slices.BinarySearch(ruleTerms, term); ok {
+			score++
+		}
+	}
+
+	return score
+}
+
+// identifiers returns the sorted, distinct lower-cased identifiers of text,
+// skipping short ones and stopTerms.
+func identifiers(text string) []string {
+	var terms []string
+
+	for _, ident := range identifierPattern.FindAllString(text, -1) {
+		ident = strings.ToLower(ident)
+		if len(ident) >= minTermLength && !stopTerms[ident] {
+			terms = append(terms, ident)
+		}
+	}
+
+	slices.Sort(terms)
+
+	return slices.Compact(terms)
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Recommend(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{AllCategories}
+
+	repoRules := []Rule{
+		{
+			Name:        "error_wrapping",
+			Category:    "code",
+			Description: "Wrap errors with fmt.Errorf and the %w verb",
+			Examples:    []Example{{Code: `return fmt.Errorf("read config: %w", err)`}},
+		},
+		{
+			Name:        "context_first",
+			Category:    "code",
+			Description: "Pass context.Context as the first parameter",
+		},
+		{
+			Name:        "legacy_errors",
+			Category:    "code",
+			Description: "Use fmt.Errorf for errors",
+			Deprecated:  true,
+		},
+		{
+			Name:        "table_tests",
+			Category:    "testing",
+			Description: "Use table driven tests with t.Run",
+		},
+		{
+			Name:        "error_checks",
+			Category:    "code",
+			Description: "Check errors with errors.Is",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		expected []string
+		limit    int
+	}{
+		{
+			name:     "ranked by shared identifiers",
+			code:     `if err := load(context.Background()); err != nil { return fmt.Errorf("load: %w", err) }`,
+			expected: []string{"error_wrapping", "context_first"},
+		},
+		{
+			name:     "ties keep canonical order",
+			code:     `errors.Is(target)`,
+			expected: []string{"error_checks", "error_wrapping"},
+		},
+		{
+			name:     "limit",
+			code:     `errors.Is(target)`,
+			limit:    1,
+			expected: []string{"error_checks"},
+		},
+		{
+			name:     "no shared identifiers",
+			code:     `x := 1`,
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().
+				GetCodeStyle(ctx, categories, Filter{}).
+				Return(repoRules, nil).
+				Maybe()
+
+			rules, err := New(mockRepo).Recommend(ctx, tt.code, categories, tt.limit)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestService_Recommend_Error(t *testing.T) {
+	ctx := context.Background()
+	repoErr := errors.New("repo failed")
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().GetCodeStyle(ctx, []string{"code"}, Filter{}).Return(nil, repoErr)
+
+	_, err := New(mockRepo).Recommend(ctx, "fmt.Errorf", []string{"code"}, 0)
+	assert.ErrorIs(t, err, repoErr)
+}
+
+func TestIdentifiers(t *testing.T) {
+	assert.Equal(t, []string{"errorf", "fmt", "load", "path"}, identifiers(`func load(path string) { return fmt.Errorf("%s", path) }`))
+	assert.Empty(t, identifiers("a + b"))
+}
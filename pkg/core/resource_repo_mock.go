@@ -23,21 +23,179 @@ func (_m *MockResourceRepo) EXPECT() *MockResourceRepo_Expecter {
 	return &MockResourceRepo_Expecter{mock: &_m.Mock}
 }
 
-// GetCodeStyle provides a mock function with given fields: ctx, categories
-func (_m *MockResourceRepo) GetCodeStyle(ctx context.Context, categories []string) ([]Rule, error) {
-	ret := _m.Called(ctx, categories)
+// Count provides a mock function with given fields: ctx
+func (_m *MockResourceRepo) Count(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCodeStyle")
+		panic("no return value specified for Count")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockResourceRepo_Count_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Count'
+type MockResourceRepo_Count_Call struct {
+	*mock.Call
+}
+
+// Count is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockResourceRepo_Expecter) Count(ctx interface{}) *MockResourceRepo_Count_Call {
+	return &MockResourceRepo_Count_Call{Call: _e.mock.On("Count", ctx)}
+}
+
+func (_c *MockResourceRepo_Count_Call) Run(run func(ctx context.Context)) *MockResourceRepo_Count_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockResourceRepo_Count_Call) Return(_a0 int, _a1 error) *MockResourceRepo_Count_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockResourceRepo_Count_Call) RunAndReturn(run func(context.Context) (int, error)) *MockResourceRepo_Count_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Diagnose provides a mock function with given fields: ctx
+func (_m *MockResourceRepo) Diagnose(ctx context.Context) Diagnostics {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Diagnose")
+	}
+
+	var r0 Diagnostics
+	if rf, ok := ret.Get(0).(func(context.Context) Diagnostics); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(Diagnostics)
+	}
+
+	return r0
+}
+
+// MockResourceRepo_Diagnose_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Diagnose'
+type MockResourceRepo_Diagnose_Call struct {
+	*mock.Call
+}
+
+// Diagnose is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockResourceRepo_Expecter) Diagnose(ctx interface{}) *MockResourceRepo_Diagnose_Call {
+	return &MockResourceRepo_Diagnose_Call{Call: _e.mock.On("Diagnose", ctx)}
+}
+
+func (_c *MockResourceRepo_Diagnose_Call) Run(run func(ctx context.Context)) *MockResourceRepo_Diagnose_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockResourceRepo_Diagnose_Call) Return(_a0 Diagnostics) *MockResourceRepo_Diagnose_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockResourceRepo_Diagnose_Call) RunAndReturn(run func(context.Context) Diagnostics) *MockResourceRepo_Diagnose_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Fingerprint provides a mock function with given fields: ctx
+func (_m *MockResourceRepo) Fingerprint(ctx context.Context) (string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fingerprint")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockResourceRepo_Fingerprint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fingerprint'
+type MockResourceRepo_Fingerprint_Call struct {
+	*mock.Call
+}
+
+// Fingerprint is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockResourceRepo_Expecter) Fingerprint(ctx interface{}) *MockResourceRepo_Fingerprint_Call {
+	return &MockResourceRepo_Fingerprint_Call{Call: _e.mock.On("Fingerprint", ctx)}
+}
+
+func (_c *MockResourceRepo_Fingerprint_Call) Run(run func(ctx context.Context)) *MockResourceRepo_Fingerprint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockResourceRepo_Fingerprint_Call) Return(_a0 string, _a1 error) *MockResourceRepo_Fingerprint_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockResourceRepo_Fingerprint_Call) RunAndReturn(run func(context.Context) (string, error)) *MockResourceRepo_Fingerprint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByNames provides a mock function with given fields: ctx, names
+func (_m *MockResourceRepo) GetByNames(ctx context.Context, names []string) ([]Rule, error) {
+	ret := _m.Called(ctx, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByNames")
 	}
 
 	var r0 []Rule
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]Rule, error)); ok {
-		return rf(ctx, categories)
+		return rf(ctx, names)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, []string) []Rule); ok {
-		r0 = rf(ctx, categories)
+		r0 = rf(ctx, names)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]Rule)
@@ -45,7 +203,66 @@ func (_m *MockResourceRepo) GetCodeStyle(ctx context.Context, categories []strin
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
-		r1 = rf(ctx, categories)
+		r1 = rf(ctx, names)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockResourceRepo_GetByNames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByNames'
+type MockResourceRepo_GetByNames_Call struct {
+	*mock.Call
+}
+
+// GetByNames is a helper method to define mock.On call
+//   - ctx context.Context
+//   - names []string
+func (_e *MockResourceRepo_Expecter) GetByNames(ctx interface{}, names interface{}) *MockResourceRepo_GetByNames_Call {
+	return &MockResourceRepo_GetByNames_Call{Call: _e.mock.On("GetByNames", ctx, names)}
+}
+
+func (_c *MockResourceRepo_GetByNames_Call) Run(run func(ctx context.Context, names []string)) *MockResourceRepo_GetByNames_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockResourceRepo_GetByNames_Call) Return(_a0 []Rule, _a1 error) *MockResourceRepo_GetByNames_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockResourceRepo_GetByNames_Call) RunAndReturn(run func(context.Context, []string) ([]Rule, error)) *MockResourceRepo_GetByNames_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCodeStyle provides a mock function with given fields: ctx, categories, filter
+func (_m *MockResourceRepo) GetCodeStyle(ctx context.Context, categories []string, filter Filter) ([]Rule, error) {
+	ret := _m.Called(ctx, categories, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCodeStyle")
+	}
+
+	var r0 []Rule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, Filter) ([]Rule, error)); ok {
+		return rf(ctx, categories, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, Filter) []Rule); ok {
+		r0 = rf(ctx, categories, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Rule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, Filter) error); ok {
+		r1 = rf(ctx, categories, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,13 +278,14 @@ type MockResourceRepo_GetCodeStyle_Call struct {
 // GetCodeStyle is a helper method to define mock.On call
 //   - ctx context.Context
 //   - categories []string
-func (_e *MockResourceRepo_Expecter) GetCodeStyle(ctx interface{}, categories interface{}) *MockResourceRepo_GetCodeStyle_Call {
-	return &MockResourceRepo_GetCodeStyle_Call{Call: _e.mock.On("GetCodeStyle", ctx, categories)}
+//   - filter Filter
+func (_e *MockResourceRepo_Expecter) GetCodeStyle(ctx interface{}, categories interface{}, filter interface{}) *MockResourceRepo_GetCodeStyle_Call {
+	return &MockResourceRepo_GetCodeStyle_Call{Call: _e.mock.On("GetCodeStyle", ctx, categories, filter)}
 }
 
-func (_c *MockResourceRepo_GetCodeStyle_Call) Run(run func(ctx context.Context, categories []string)) *MockResourceRepo_GetCodeStyle_Call {
+func (_c *MockResourceRepo_GetCodeStyle_Call) Run(run func(ctx context.Context, categories []string, filter Filter)) *MockResourceRepo_GetCodeStyle_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].([]string))
+		run(args[0].(context.Context), args[1].([]string), args[2].(Filter))
 	})
 	return _c
 }
@@ -77,7 +295,63 @@ func (_c *MockResourceRepo_GetCodeStyle_Call) Return(_a0 []Rule, _a1 error) *Moc
 	return _c
 }
 
-func (_c *MockResourceRepo_GetCodeStyle_Call) RunAndReturn(run func(context.Context, []string) ([]Rule, error)) *MockResourceRepo_GetCodeStyle_Call {
+func (_c *MockResourceRepo_GetCodeStyle_Call) RunAndReturn(run func(context.Context, []string, Filter) ([]Rule, error)) *MockResourceRepo_GetCodeStyle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function with given fields: ctx
+func (_m *MockResourceRepo) Stats(ctx context.Context) (RepoStats, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 RepoStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (RepoStats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) RepoStats); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(RepoStats)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockResourceRepo_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type MockResourceRepo_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockResourceRepo_Expecter) Stats(ctx interface{}) *MockResourceRepo_Stats_Call {
+	return &MockResourceRepo_Stats_Call{Call: _e.mock.On("Stats", ctx)}
+}
+
+func (_c *MockResourceRepo_Stats_Call) Run(run func(ctx context.Context)) *MockResourceRepo_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockResourceRepo_Stats_Call) Return(_a0 RepoStats, _a1 error) *MockResourceRepo_Stats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockResourceRepo_Stats_Call) RunAndReturn(run func(context.Context) (RepoStats, error)) *MockResourceRepo_Stats_Call {
 	_c.Call.Return(run)
 	return _c
 }
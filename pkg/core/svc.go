@@ -6,26 +6,182 @@
 package core
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
+// AllCategories is a wildcard category that matches rules of every category.
+const AllCategories = "*"
+
+// CategorySeparator delimits the levels of hierarchical categories, e.g. "code/concurrency".
+const CategorySeparator = "/"
+
+// MatchesCategory reports whether a rule in category is selected by the requested category.
+// A request selects its own category and all of its descendants, so "code" matches
+// "code/concurrency" but not "codegen"; AllCategories matches every category.
+func MatchesCategory(category, requested string) bool {
+	return requested == AllCategories || category == requested || strings.HasPrefix(category, requested+CategorySeparator)
+}
+
 // ResourceRepo defines the interface for managing code generation rules and resources.
 // It provides methods to retrieve rules by categories and language.
 type ResourceRepo interface {
-	// GetCodeStyle returns all rules that match the specified categories
-	GetCodeStyle(ctx context.Context, categories []string) ([]Rule, error)
+	// GetCodeStyle returns all rules that match the specified categories and filter.
+	// Categories match as described by MatchesCategory.
+	GetCodeStyle(ctx context.Context, categories []string, filter Filter) ([]Rule, error)
+	// GetByNames returns all rules whose name is one of names, in any order.
+	// Names that match no rule are silently skipped.
+	GetByNames(ctx context.Context, names []string) ([]Rule, error)
+	// Stats returns aggregate metrics about the stored rules
+	Stats(ctx context.Context) (RepoStats, error)
+	// Count returns the number of stored rules without materializing them
+	Count(ctx context.Context) (int, error)
+	// Fingerprint returns a hash of the stored rules independent of their order, see FingerprintRules
+	Fingerprint(ctx context.Context) (string, error)
+	// Diagnose returns a snapshot of the repository state logged at startup.
+	// It must be fast and never fail; failed checks are reported as problems.
+	Diagnose(ctx context.Context) Diagnostics
+}
+
+// RepoStats holds aggregate metrics about the rules stored in a repository.
+type RepoStats struct {
+	RulesPerCategory  map[string]int `json:"rules_per_category"`
+	TotalRules        int            `json:"total_rules"`
+	RulesWithExamples int            `json:"rules_with_examples"`
+}
+
+// Diagnostics is a snapshot of the state of a repository, see ResourceRepo.Diagnose.
+type Diagnostics struct {
+	// Type names the repository implementation, e.g. "static"
+	Type string `json:"type"`
+	// Categories lists the sorted distinct categories of the stored rules
+	Categories []string `json:"categories"`
+	// Problems lists the failed checks, such as examples failing validation or an
+	// unreachable backend. They are advisory and do not prevent serving rules.
+	Problems []string `json:"problems,omitempty"`
+	// Rules is the number of stored rules
+	Rules int `json:"rules"`
+}
+
+// Filter holds optional criteria that narrow down the rules matched by categories.
+// The zero value applies no additional filtering. Repositories only need to apply
+// CodeContains; the other criteria are enforced by Service on the returned rules.
+type Filter struct {
+	// UpdatedSince keeps only rules updated at or after this time, zero means all.
+	// Rules without an update time are excluded when it is set.
+	UpdatedSince time.Time
+	// CategoryWeights maps requested categories to weights; rules of higher-weighted categories
+	// come first, in the order selected by SortBy otherwise. A rule takes the highest weight of
+	// the categories matching it, and rules matching no weighted category weigh 1.
+	CategoryWeights map[string]int
+	// CodeContains keeps only rules with an example whose code contains this substring,
+	// ignoring case and diacritics, see NormalizeText
+	CodeContains string
+	// Language keeps only rules of this language, compared case-insensitively; empty means all.
+	// When no rule matches, the languages of the fallback chain are tried in turn, see
+	// SetLanguageFallback, so the language of the returned rules tells which one supplied them.
+	Language string
+	// MinSeverity keeps only rules at least as strict as this severity, empty means all.
+	MinSeverity string
+	// SortBy selects the order of returned rules, one of the Sort* constants; empty means SortCanonical.
+	SortBy string
+	// ExcludeCategories drops rules matching any of these categories, see MatchesCategory,
+	// even when a requested category includes them.
+	ExcludeCategories []string
+	// PerCategoryLimit caps the number of rules returned per category, zero means unlimited.
+	PerCategoryLimit int
+	// MaxExamplesPerRule caps the number of examples of each rule, zero means unlimited.
+	// Examples matching CodeContains are kept first.
+	MaxExamplesPerRule int
+	// IncludeDeprecated keeps deprecated rules, which are excluded by default.
+	IncludeDeprecated bool
+	// WithExamplesOnly keeps only rules with at least one example.
+	WithExamplesOnly bool
 }
 
+// Supported rule orders for Filter.SortBy.
+const (
+	// SortCanonical orders rules by category, then by name
+	SortCanonical = "canonical"
+	// SortUpdated orders rules from the most to the least recently updated; rules without an update time sort last
+	SortUpdated = "updated"
+	// SortSeverity orders rules from the most to the least strict severity, then canonically
+	SortSeverity = "severity"
+	// SortName orders rules by name across categories
+	SortName = "name"
+	// SortManual orders rules by ascending Rule.Order; rules without an order sort last,
+	// and rules with equal order keep the repository order
+	SortManual = "manual"
+	// SortPopularity orders rules from the most to the least returned, see Service.RuleHits,
+	// then canonically; counts reset when the repository is replaced
+	SortPopularity = "popularity"
+)
+
 // Rule defines a universal structure for all types of code generation rules.
 // It encapsulates the complete definition of a code generation rule including
 // its metadata and examples.
 type Rule struct {
-	Name        string    `json:"name"`
-	Category    string    `json:"category"` // One of: "documentation", "testing", "code"
-	Description string    `json:"description"`
-	Examples    []Example `json:"examples"`
+	UpdatedAt       time.Time `json:"updated_at,omitzero"` // When the rule was last changed, zero if unknown
+	Name            string    `json:"name"`
+	Category        string    `json:"category"` // One of: "documentation", "testing", "code"
+	Description     string    `json:"description"`
+	Severity        string    `json:"severity,omitempty"`         // One of: "must", "should", "may"
+	DeprecationNote string    `json:"deprecation_note,omitempty"` // Optional reason the rule was deprecated
+	Language        string    `json:"language,omitempty"`         // Language of the examples, e.g. "go", used as the code fence tag
+	Template        string    `json:"template,omitempty"`         // Optional text/template scaffolding, see RenderTemplate
+	Examples        []Example `json:"examples"`
+	ConflictsWith   []string  `json:"conflicts_with,omitempty"` // Names of rules giving contradictory advice
+	Order           int       `json:"order,omitempty"`          // Position in the SortManual order, zero if unset
+	Deprecated      bool      `json:"deprecated,omitempty"`
+}
+
+// Rule severities following RFC 2119 requirement levels.
+const (
+	SeverityMust   = "must"
+	SeverityShould = "should"
+	SeverityMay    = "may"
+)
+
+// DefaultSeverity is assigned to rules that do not declare a severity.
+const DefaultSeverity = SeverityShould
+
+// severityRanks orders severities from least to most strict.
+var severityRanks = map[string]int{
+	SeverityMay:    1,
+	SeverityShould: 2,
+	SeverityMust:   3,
+}
+
+// IsValidSeverity reports whether severity is one of the supported severities.
+func IsValidSeverity(severity string) bool {
+	_, ok := severityRanks[severity]
+	return ok
+}
+
+// deprecationNotice returns the notice shown for deprecated rules.
+func (r *Rule) deprecationNotice() string {
+	if r.DeprecationNote == "" {
+		return "DEPRECATED"
+	}
+
+	return "DEPRECATED: " + r.DeprecationNote
+}
+
+// severityLabel returns the severity as an upper-case tag, e.g. "[MUST]",
+// or an empty string if the rule has no severity.
+func (r *Rule) severityLabel() string {
+	if r.Severity == "" {
+		return ""
+	}
+
+	return "[" + strings.ToUpper(r.Severity) + "]"
 }
 
 // FormatForLLM returns a concise, token-optimized string representation of the rule
@@ -33,9 +189,22 @@ type Rule struct {
 func (r *Rule) FormatForLLM() string {
 	var parts []string
 
-	// Always include name and description as they're essential
+	// Always include name and description as they're essential, prefixed by severity
+	header := r.severityLabel()
 	if r.Description != "" {
-		parts = append(parts, fmt.Sprintf("Description: %s", r.Description))
+		header = strings.TrimSpace(fmt.Sprintf("%s Description: %s", header, r.Description))
+	}
+
+	if header != "" {
+		parts = append(parts, header)
+	}
+
+	if r.Deprecated {
+		parts = append(parts, r.deprecationNotice())
+	}
+
+	if !r.UpdatedAt.IsZero() {
+		parts = append(parts, "Updated: "+r.UpdatedAt.Format(time.RFC3339))
 	}
 
 	// Include examples if present
@@ -44,28 +213,181 @@ func (r *Rule) FormatForLLM() string {
 
 		for _, ex := range r.Examples {
 			if ex.Description != "" && ex.Code != "" {
-				examples = append(examples, fmt.Sprintf("Example (%s):\n```\n%s```", ex.Description, ex.Code))
+				examples = append(examples, fmt.Sprintf("%s (%s)%s:\n%s\n%s```", ex.label(), ex.Description, ex.formatMetadata(), ex.fence(r.Language), ex.Code))
 			}
 		}
 
 		parts = append(parts, strings.Join(examples, "\n"))
 	}
 
+	if r.Template != "" {
+		parts = append(parts, fmt.Sprintf("Template:\n%s\n%s```", r.templateFence(), r.Template))
+	}
+
 	return strings.Join(parts, "\n")
 }
 
+// FormatForLLMWithCategory returns the FormatForLLM representation preceded by a
+// "Category:" line, so that rules stay distinguishable when several categories are joined.
+func (r *Rule) FormatForLLMWithCategory() string {
+	return r.categoryLine() + r.FormatForLLM()
+}
+
+// FormatSummary returns a compact representation of the rule with only its name
+// and description, omitting examples. It is intended for token-sensitive responses.
+func (r *Rule) FormatSummary() string {
+	parts := []string{strings.TrimSpace(fmt.Sprintf("%s Name: %s", r.severityLabel(), r.Name))}
+
+	if r.Description != "" {
+		parts = append(parts, fmt.Sprintf("Description: %s", r.Description))
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// FormatSummaryWithCategory returns the FormatSummary representation preceded by a "Category:" line.
+func (r *Rule) FormatSummaryWithCategory() string {
+	return r.categoryLine() + r.FormatSummary()
+}
+
+// categoryLine returns the "Category:" line prefixed by the *WithCategory formats.
+func (r *Rule) categoryLine() string {
+	return "Category: " + r.Category + "\n"
+}
+
+// FormatMarkdown returns a markdown representation of the rule with a heading
+// for the rule name and a subsection with a fenced code block per example.
+// It is intended for clients that render markdown content.
+func (r *Rule) FormatMarkdown() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "## %s\n", strings.TrimSpace(r.Name+" "+r.severityLabel()))
+
+	if r.Deprecated {
+		fmt.Fprintf(&sb, "\n> **%s**\n", r.deprecationNotice())
+	}
+
+	if r.Description != "" {
+		fmt.Fprintf(&sb, "\n%s\n", r.Description)
+	}
+
+	if !r.UpdatedAt.IsZero() {
+		fmt.Fprintf(&sb, "\n_Updated: %s_\n", r.UpdatedAt.Format(time.RFC3339))
+	}
+
+	for _, ex := range r.Examples {
+		if ex.Code == "" {
+			continue
+		}
+
+		if ex.Description != "" {
+			heading := ex.Description
+			if ex.IsAntiPattern() {
+				heading = "Anti-pattern: " + heading
+			}
+
+			fmt.Fprintf(&sb, "\n### %s%s\n", heading, ex.formatMetadata())
+		}
+
+		fmt.Fprintf(&sb, "\n%s\n%s\n```\n", ex.fence(r.Language), strings.TrimRight(ex.Code, "\n"))
+	}
+
+	if r.Template != "" {
+		fmt.Fprintf(&sb, "\n### Template\n\n%s\n%s\n```\n", r.templateFence(), strings.TrimRight(r.Template, "\n"))
+	}
+
+	return sb.String()
+}
+
+// templateFence returns the opening code fence of the rule template, tagged with the rule language.
+func (r *Rule) templateFence() string {
+	return "```" + strings.ToLower(r.Language)
+}
+
+// MetadataGood is the example metadata key that marks an example as good (true)
+// or as an anti-pattern (false).
+const MetadataGood = "good"
+
 // Example provides a usage example for a rule.
 // It includes a description of what the example demonstrates,
 // the actual code snippet, and the context in which it applies.
+// Metadata holds optional structured attributes, such as MetadataGood or tags,
+// whose values may be strings, numbers or booleans.
+// Language overrides the language of the rule for this example.
+// Examples are served by ascending Order, followed by examples without one.
 type Example struct {
-	Description string `json:"description"`
-	Code        string `json:"code"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Description string         `json:"description"`
+	Code        string         `json:"code"`
+	Language    string         `json:"language,omitempty"`
+	Order       int            `json:"order,omitempty"` // Position among the rule examples, zero if unset
+}
+
+// IsAntiPattern reports whether the example is marked as an anti-pattern
+// with a false MetadataGood value, given either as a boolean or a string.
+func (e *Example) IsAntiPattern() bool {
+	switch v := e.Metadata[MetadataGood].(type) {
+	case bool:
+		return !v
+	case string:
+		good, err := strconv.ParseBool(v)
+		return err == nil && !good
+	default:
+		return false
+	}
+}
+
+// fence returns the opening code fence of the example, tagged with the example
+// language or, if it has none, with ruleLanguage. When neither is set, the language
+// is guessed from the code; the fence is untagged if it cannot be told.
+func (e *Example) fence(ruleLanguage string) string {
+	return "```" + strings.ToLower(cmp.Or(e.Language, ruleLanguage, detectLanguage(e.Code)))
+}
+
+// label returns the heading used for the example when rendering a rule.
+func (e *Example) label() string {
+	if e.IsAntiPattern() {
+		return "Anti-pattern"
+	}
+
+	return "Example"
+}
+
+// formatMetadata renders metadata other than MetadataGood as a bracketed,
+// key-sorted list, or an empty string if there is none.
+func (e *Example) formatMetadata() string {
+	keys := make([]string, 0, len(e.Metadata))
+
+	for k := range e.Metadata {
+		if k != MetadataGood {
+			keys = append(keys, k)
+		}
+	}
+
+	if len(keys) == 0 {
+		return ""
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, e.Metadata[k])
+	}
+
+	return " [" + strings.Join(pairs, ", ") + "]"
 }
 
 // Service implements the core business logic for rule management.
-// This is safe for concurrent use as it delegates operations to the underlying repository.
+// This is safe for concurrent use as it delegates operations to the underlying repository,
+// which can be replaced at runtime with SetRepo.
 type Service struct {
 	resource ResourceRepo
+	hits     map[string]int    // returned count per rule name, see RuleHits
+	audit    *AuditLog         // optional sink of every query, see SetAuditLog
+	fallback map[string]string // next language to try per lower-cased language, see SetLanguageFallback
+	mu       sync.RWMutex
+	hitsMu   sync.Mutex
 }
 
 // New creates a new Service instance with the provided resource repository.
@@ -76,11 +398,495 @@ func New(resource ResourceRepo) *Service {
 	}
 }
 
-// GetCodeStyle retrieves rules that match the specified categories.
+// GetCodeStyle retrieves rules that match the specified categories and filter.
+// Rules are returned in canonical order (by category, then name) regardless of
+// the repository, so responses are deterministic, unless filter.SortBy selects
+// another order.
 // It returns a slice of rules and any error encountered during the retrieval.
+// Unlike GetByNames and Recommend, it does not record the query, see RecordCodeStyle.
 // Returns error if the repository access fails.
-func (s *Service) GetCodeStyle(ctx context.Context, categories []string) ([]Rule, error) {
-	return s.resource.GetCodeStyle(ctx, categories)
+func (s *Service) GetCodeStyle(ctx context.Context, categories []string, filter Filter) ([]Rule, error) {
+	rules, err := s.repo().GetCodeStyle(ctx, categories, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rules = withoutCategories(rules, filter.ExcludeCategories)
+
+	if !filter.IncludeDeprecated {
+		rules = withoutDeprecated(rules)
+	}
+
+	if filter.WithExamplesOnly {
+		rules = withExamples(rules)
+	}
+
+	rules = filterBySeverity(rules, filter.MinSeverity)
+
+	if !filter.UpdatedSince.IsZero() {
+		rules = updatedSince(rules, filter.UpdatedSince)
+	}
+
+	rules = s.selectLanguage(rules, filter.Language)
+
+	rules = s.orderRules(rules, filter.SortBy)
+	rules = sortByCategoryWeight(rules, filter.CategoryWeights)
+
+	rules = sortExamples(rules)
+	rules = limitExamples(rules, filter.MaxExamplesPerRule, filter.CodeContains)
+	rules = LimitPerCategory(rules, filter.PerCategoryLimit)
+
+	return rules, nil
+}
+
+// RecordCodeStyle records a codestyle query for categories and filter that returned rules:
+// the hit counter of each rule is incremented and the query is appended to the audit log.
+// GetCodeStyle leaves recording to its callers, which may fetch more rules than they
+// return, e.g. to detect truncation, or query again with a broader filter.
+func (s *Service) RecordCodeStyle(ctx context.Context, categories []string, filter Filter, rules []Rule) {
+	s.recordHits(rules)
+	s.recordQuery(ctx, AuditRecord{Query: AuditQueryCodeStyle, Categories: categories, CodeContains: filter.CodeContains}, len(rules))
+}
+
+// RecordByNames records a getrules query for names that returned rules: the hit counter
+// of each rule is incremented and the query is appended to the audit log. Like
+// GetCodeStyle, GetByNames leaves recording to its callers, which may drop rules,
+// e.g. those outside the allowed categories, before returning them.
+func (s *Service) RecordByNames(ctx context.Context, names []string, rules []Rule) {
+	s.recordHits(rules)
+	s.recordQuery(ctx, AuditRecord{Query: AuditQueryByNames, Names: names}, len(rules))
+}
+
+// IsValidSort reports whether sortBy is one of the supported rule orders.
+func IsValidSort(sortBy string) bool {
+	switch sortBy {
+	case SortCanonical, SortUpdated, SortSeverity, SortName, SortManual, SortPopularity:
+		return true
+	default:
+		return false
+	}
+}
+
+// orderRules returns a copy of rules in the order selected by sortBy, one of the
+// Sort* constants; an empty or unknown sortBy means SortCanonical.
+func (s *Service) orderRules(rules []Rule, sortBy string) []Rule {
+	switch sortBy {
+	case SortPopularity:
+		return s.sortByPopularity(sortRules(rules))
+	case SortManual:
+		// Sorted from the repository order, which is the config order for static rules
+		return sortByOrder(rules)
+	case SortUpdated:
+		return sortByUpdated(sortRules(rules))
+	case SortSeverity:
+		return sortBySeverity(sortRules(rules))
+	case SortName:
+		return sortByName(sortRules(rules))
+	default:
+		return sortRules(rules)
+	}
+}
+
+// sortByOrder returns a copy of rules sorted by ascending Rule.Order, with rules
+// without an order last. The sort is stable, so rules with equal order keep their order.
+func sortByOrder(rules []Rule) []Rule {
+	sorted := slices.Clone(rules)
+
+	slices.SortStableFunc(sorted, func(a, b Rule) int {
+		return cmp.Or(
+			cmp.Compare(boolRank(a.Order == 0), boolRank(b.Order == 0)),
+			cmp.Compare(a.Order, b.Order),
+		)
+	})
+
+	return sorted
+}
+
+// sortByCategoryWeight returns a copy of rules sorted from the highest to the lowest
+// category weight, as described by Filter.CategoryWeights. The sort is stable, so rules
+// with equal weight keep their order. Rules are returned as is when there are no weights.
+func sortByCategoryWeight(rules []Rule, weights map[string]int) []Rule {
+	if len(weights) == 0 {
+		return rules
+	}
+
+	sorted := slices.Clone(rules)
+
+	slices.SortStableFunc(sorted, func(a, b Rule) int {
+		return cmp.Compare(categoryWeight(b.Category, weights), categoryWeight(a.Category, weights))
+	})
+
+	return sorted
+}
+
+// categoryWeight returns the highest weight of the requested categories matching category,
+// or 1 if none of them is weighted.
+func categoryWeight(category string, weights map[string]int) int {
+	weight := 0
+
+	for requested, w := range weights {
+		if MatchesCategory(category, requested) {
+			weight = max(weight, w)
+		}
+	}
+
+	if weight == 0 {
+		return 1
+	}
+
+	return weight
+}
+
+// sortBySeverity returns a copy of rules sorted from the most to the least strict
+// severity, treating rules without a severity as DefaultSeverity. The sort is
+// stable, so rules with equal severity keep their order.
+func sortBySeverity(rules []Rule) []Rule {
+	sorted := slices.Clone(rules)
+
+	slices.SortStableFunc(sorted, func(a, b Rule) int {
+		return cmp.Compare(severityRank(b.Severity), severityRank(a.Severity))
+	})
+
+	return sorted
+}
+
+// sortByName returns a copy of rules sorted by name. The sort is stable, so rules
+// with equal names keep their order.
+func sortByName(rules []Rule) []Rule {
+	sorted := slices.Clone(rules)
+
+	slices.SortStableFunc(sorted, func(a, b Rule) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return sorted
+}
+
+// severityRank returns the strictness rank of severity, treating an empty severity as DefaultSeverity.
+func severityRank(severity string) int {
+	return severityRanks[cmp.Or(severity, DefaultSeverity)]
+}
+
+// updatedSince returns the rules updated at or after since.
+func updatedSince(rules []Rule, since time.Time) []Rule {
+	filtered := make([]Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		if !rule.UpdatedAt.IsZero() && !rule.UpdatedAt.Before(since) {
+			filtered = append(filtered, rule)
+		}
+	}
+
+	return filtered
+}
+
+// sortByUpdated returns a copy of rules sorted from the most to the least recently
+// updated, with rules without an update time last. The sort is stable, so rules
+// with equal update times keep their order.
+func sortByUpdated(rules []Rule) []Rule {
+	sorted := slices.Clone(rules)
+
+	slices.SortStableFunc(sorted, func(a, b Rule) int {
+		return cmp.Or(
+			cmp.Compare(boolRank(a.UpdatedAt.IsZero()), boolRank(b.UpdatedAt.IsZero())),
+			b.UpdatedAt.Compare(a.UpdatedAt),
+		)
+	})
+
+	return sorted
+}
+
+// boolRank maps false to 0 and true to 1, for use in comparisons.
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// sortRules returns a copy of rules sorted by category and then by name.
+// The sort is stable, so rules with equal keys keep the repository order.
+func sortRules(rules []Rule) []Rule {
+	sorted := slices.Clone(rules)
+
+	slices.SortStableFunc(sorted, func(a, b Rule) int {
+		return cmp.Or(
+			cmp.Compare(a.Category, b.Category),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	return sorted
+}
+
+// withoutCategories returns the rules not matching any of the excluded categories.
+func withoutCategories(rules []Rule, excluded []string) []Rule {
+	if len(excluded) == 0 {
+		return rules
+	}
+
+	kept := make([]Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		if !slices.ContainsFunc(excluded, func(cat string) bool { return MatchesCategory(rule.Category, cat) }) {
+			kept = append(kept, rule)
+		}
+	}
+
+	return kept
+}
+
+// withoutDeprecated returns the rules that are not deprecated.
+func withoutDeprecated(rules []Rule) []Rule {
+	active := make([]Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		if !rule.Deprecated {
+			active = append(active, rule)
+		}
+	}
+
+	return active
+}
+
+// Conflicts returns the pairs of rules among rules that declare a conflict with
+// each other, in either direction. Each pair is reported once, ordered by name,
+// and pairs are sorted.
+func Conflicts(rules []Rule) [][2]string {
+	present := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		present[rule.Name] = true
+	}
+
+	var pairs [][2]string
+
+	for _, rule := range rules {
+		for _, other := range rule.ConflictsWith {
+			if !present[other] || other == rule.Name {
+				continue
+			}
+
+			pair := [2]string{min(rule.Name, other), max(rule.Name, other)}
+			if !slices.Contains(pairs, pair) {
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+
+	slices.SortFunc(pairs, func(a, b [2]string) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+
+	return pairs
+}
+
+// withExamples returns the rules that have at least one example.
+func withExamples(rules []Rule) []Rule {
+	filtered := make([]Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		if len(rule.Examples) > 0 {
+			filtered = append(filtered, rule)
+		}
+	}
+
+	return filtered
+}
+
+// limitExamples returns rules with at most limit examples each, without modifying
+// the examples of the provided rules. Examples whose code contains codeContains,
+// ignoring case and diacritics, are kept first, and kept examples stay in their original order.
+// A limit of zero or less keeps all examples.
+func limitExamples(rules []Rule, limit int, codeContains string) []Rule {
+	if limit <= 0 {
+		return rules
+	}
+
+	limited := make([]Rule, len(rules))
+
+	for i, rule := range rules {
+		if len(rule.Examples) > limit {
+			rule.Examples = selectExamples(rule.Examples, limit, NormalizeText(codeContains))
+		}
+
+		limited[i] = rule
+	}
+
+	return limited
+}
+
+// sortExamples returns rules with the examples of each rule sorted by ascending
+// Example.Order, with examples without an order last. The sort is stable, so examples
+// with equal order keep their repository order. Rules without ordered examples are kept as is.
+func sortExamples(rules []Rule) []Rule {
+	sorted := make([]Rule, len(rules))
+
+	for i, rule := range rules {
+		if slices.ContainsFunc(rule.Examples, func(ex Example) bool { return ex.Order != 0 }) {
+			rule.Examples = slices.Clone(rule.Examples)
+
+			slices.SortStableFunc(rule.Examples, func(a, b Example) int {
+				return cmp.Or(
+					cmp.Compare(boolRank(a.Order == 0), boolRank(b.Order == 0)),
+					cmp.Compare(a.Order, b.Order),
+				)
+			})
+		}
+
+		sorted[i] = rule
+	}
+
+	return sorted
+}
+
+// selectExamples returns limit examples, preferring those whose normalized code
+// contains substr, in their original order. substr must be normalized with NormalizeText.
+func selectExamples(examples []Example, limit int, substr string) []Example {
+	keep := make([]bool, len(examples))
+	kept := 0
+
+	if substr != "" {
+		for i, ex := range examples {
+			if kept < limit && strings.Contains(NormalizeText(ex.Code), substr) {
+				keep[i] = true
+				kept++
+			}
+		}
+	}
+
+	for i := range examples {
+		if kept < limit && !keep[i] {
+			keep[i] = true
+			kept++
+		}
+	}
+
+	selected := make([]Example, 0, limit)
+
+	for i, ex := range examples {
+		if keep[i] {
+			selected = append(selected, ex)
+		}
+	}
+
+	return selected
+}
+
+// filterBySeverity keeps rules whose severity is at least minSeverity.
+// Rules without a severity are treated as DefaultSeverity.
+// An empty or unknown minSeverity keeps all rules.
+func filterBySeverity(rules []Rule, minSeverity string) []Rule {
+	minRank, ok := severityRanks[minSeverity]
+	if !ok {
+		return rules
+	}
+
+	filtered := make([]Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		if severityRank(rule.Severity) >= minRank {
+			filtered = append(filtered, rule)
+		}
+	}
+
+	return filtered
+}
+
+// LimitPerCategory keeps at most limit rules of each category, preserving their order.
+// A limit of zero or less keeps all rules.
+func LimitPerCategory(rules []Rule, limit int) []Rule {
+	if limit <= 0 {
+		return rules
+	}
+
+	counts := make(map[string]int)
+	limited := make([]Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		if counts[rule.Category] < limit {
+			counts[rule.Category]++
+
+			limited = append(limited, rule)
+		}
+	}
+
+	return limited
+}
+
+// GetByNames retrieves the rules with the given names.
+// Rules are returned in the order their names were requested; when several rules
+// share a name, all of them are returned in canonical order. Duplicate names are ignored.
+// It also returns the requested names that matched no rule, so callers can detect stale references.
+// Returns error if the repository access fails.
+func (s *Service) GetByNames(ctx context.Context, names []string) (rules []Rule, missing []string, err error) {
+	found, err := s.repo().GetByNames(ctx, names)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byName := make(map[string][]Rule, len(found))
+	for _, rule := range sortRules(found) {
+		byName[rule.Name] = append(byName[rule.Name], rule)
+	}
+
+	rules = make([]Rule, 0, len(found))
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+
+		matched, ok := byName[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+
+		rules = append(rules, matched...)
+	}
+
+	return sortExamples(rules), missing, nil
+}
+
+// Stats returns aggregate metrics about the rules in the current repository.
+// Returns error if the repository access fails.
+func (s *Service) Stats(ctx context.Context) (RepoStats, error) {
+	return s.repo().Stats(ctx)
+}
+
+// Count returns the number of rules in the current repository.
+// Returns error if the repository access fails.
+func (s *Service) Count(ctx context.Context) (int, error) {
+	return s.repo().Count(ctx)
+}
+
+// Diagnose returns a snapshot of the state of the current repository.
+func (s *Service) Diagnose(ctx context.Context) Diagnostics {
+	return s.repo().Diagnose(ctx)
+}
+
+// SetRepo atomically replaces the repository used to serve rules.
+// Requests already in progress complete against the previous repository.
+// Rule hit counters are reset, as they refer to the rules of the previous repository.
+func (s *Service) SetRepo(resource ResourceRepo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resource = resource
+
+	s.resetHits()
+}
+
+// repo returns the current repository.
+func (s *Service) repo() ResourceRepo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.resource
 }
 
 // String implements the Stringer interface for Rule.
@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -46,6 +47,93 @@ func TestRule_FormatForLLM(t *testing.T) {
 			},
 			expected: "",
 		},
+		{
+			name: "rule with severity",
+			rule: Rule{
+				Name:        "TestRule",
+				Description: "Test description",
+				Severity:    SeverityMust,
+			},
+			expected: "[MUST] Description: Test description",
+		},
+		{
+			name: "deprecated rule with note",
+			rule: Rule{
+				Name:            "TestRule",
+				Description:     "Test description",
+				Deprecated:      true,
+				DeprecationNote: "use NewRule instead",
+			},
+			expected: "Description: Test description\nDEPRECATED: use NewRule instead",
+		},
+		{
+			name: "deprecated rule without note",
+			rule: Rule{
+				Name:        "TestRule",
+				Description: "Test description",
+				Deprecated:  true,
+			},
+			expected: "Description: Test description\nDEPRECATED",
+		},
+		{
+			name: "examples with metadata",
+			rule: Rule{
+				Name:        "TestRule",
+				Category:    "testing",
+				Description: "Test description",
+				Examples: []Example{
+					{
+						Description: "Bad",
+						Code:        "code1",
+						Metadata:    map[string]any{MetadataGood: false, "version": 2},
+					},
+					{
+						Description: "Good",
+						Code:        "code2",
+						Metadata:    map[string]any{MetadataGood: true, "tags": "errors"},
+					},
+				},
+			},
+			expected: "Description: Test description\nAnti-pattern (Bad) [version=2]:\n```\ncode1```\nExample (Good) [tags=errors]:\n```\ncode2```",
+		},
+		{
+			name: "rule with update time",
+			rule: Rule{
+				Name:        "TestRule",
+				Description: "Test description",
+				UpdatedAt:   time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+			},
+			expected: "Description: Test description\nUpdated: 2024-01-02T15:04:05Z",
+		},
+		{
+			name: "rule with template",
+			rule: Rule{
+				Name:        "TestRule",
+				Description: "Test description",
+				Template:    "type {{.name}} struct{}\n",
+			},
+			expected: "Description: Test description\nTemplate:\n```\ntype {{.name}} struct{}\n```",
+		},
+		{
+			name: "examples with languages",
+			rule: Rule{
+				Name:        "TestRule",
+				Description: "Test description",
+				Language:    "python",
+				Examples: []Example{
+					{
+						Description: "Comprehension",
+						Code:        "squares = [x * x for x in xs]",
+					},
+					{
+						Description: "Query",
+						Code:        "SELECT 1",
+						Language:    "sql",
+					},
+				},
+			},
+			expected: "Description: Test description\nExample (Comprehension):\n```python\nsquares = [x * x for x in xs]```\nExample (Query):\n```sql\nSELECT 1```",
+		},
 	}
 
 	for _, tt := range tests {
@@ -56,6 +144,278 @@ func TestRule_FormatForLLM(t *testing.T) {
 	}
 }
 
+func TestRule_FormatSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		rule     Rule
+	}{
+		{
+			name: "rule with examples",
+			rule: Rule{
+				Name:        "TestRule",
+				Category:    "testing",
+				Description: "Test description",
+				Examples: []Example{
+					{
+						Description: "Example 1",
+						Code:        "code1",
+					},
+				},
+			},
+			expected: "Name: TestRule\nDescription: Test description",
+		},
+		{
+			name: "rule with empty description",
+			rule: Rule{
+				Name:     "TestRule",
+				Category: "testing",
+			},
+			expected: "Name: TestRule",
+		},
+		{
+			name: "rule with severity",
+			rule: Rule{
+				Name:        "TestRule",
+				Description: "Test description",
+				Severity:    SeverityMay,
+			},
+			expected: "[MAY] Name: TestRule\nDescription: Test description",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.rule.FormatSummary()
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestRule_FormatWithCategory(t *testing.T) {
+	rule := Rule{
+		Name:        "TestRule",
+		Category:    "testing",
+		Description: "Test description",
+		Severity:    SeverityMust,
+		Examples:    []Example{{Description: "Example 1", Code: "code1"}},
+	}
+
+	assert.Equal(t, "Category: testing\n[MUST] Description: Test description\nExample (Example 1):\n```\ncode1```", rule.FormatForLLMWithCategory())
+	assert.Equal(t, "Category: testing\n[MUST] Name: TestRule\nDescription: Test description", rule.FormatSummaryWithCategory())
+}
+
+func TestRule_FormatMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		rule     Rule
+	}{
+		{
+			name: "full rule with examples",
+			rule: Rule{
+				Name:        "TestRule",
+				Category:    "testing",
+				Description: "Test description",
+				Examples: []Example{
+					{
+						Description: "Example 1",
+						Code:        "code1\n",
+					},
+				},
+			},
+			expected: "## TestRule\n\nTest description\n\n### Example 1\n\n```\ncode1\n```\n",
+		},
+		{
+			name: "rule with severity",
+			rule: Rule{
+				Name:     "TestRule",
+				Severity: SeverityShould,
+			},
+			expected: "## TestRule [SHOULD]\n",
+		},
+		{
+			name: "deprecated rule",
+			rule: Rule{
+				Name:            "TestRule",
+				Description:     "Test description",
+				Deprecated:      true,
+				DeprecationNote: "use NewRule instead",
+			},
+			expected: "## TestRule\n\n> **DEPRECATED: use NewRule instead**\n\nTest description\n",
+		},
+		{
+			name: "anti-pattern example",
+			rule: Rule{
+				Name: "TestRule",
+				Examples: []Example{
+					{
+						Description: "Bad",
+						Code:        "code1",
+						Metadata:    map[string]any{MetadataGood: false},
+					},
+				},
+			},
+			expected: "## TestRule\n\n### Anti-pattern: Bad\n\n```\ncode1\n```\n",
+		},
+		{
+			name: "example without description",
+			rule: Rule{
+				Name: "TestRule",
+				Examples: []Example{
+					{
+						Code: "code1",
+					},
+				},
+			},
+			expected: "## TestRule\n\n```\ncode1\n```\n",
+		},
+		{
+			name: "rule without examples",
+			rule: Rule{
+				Name:        "TestRule",
+				Description: "Test description",
+			},
+			expected: "## TestRule\n\nTest description\n",
+		},
+		{
+			name: "rule with update time",
+			rule: Rule{
+				Name:        "TestRule",
+				Description: "Test description",
+				UpdatedAt:   time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+			},
+			expected: "## TestRule\n\nTest description\n\n_Updated: 2024-01-02T15:04:05Z_\n",
+		},
+		{
+			name: "rule with template",
+			rule: Rule{
+				Name:     "TestRule",
+				Language: "go",
+				Template: "type {{.name}} struct{}\n",
+			},
+			expected: "## TestRule\n\n### Template\n\n```go\ntype {{.name}} struct{}\n```\n",
+		},
+		{
+			name: "rule language tags fences",
+			rule: Rule{
+				Name:     "TestRule",
+				Language: "python",
+				Examples: []Example{
+					{
+						Description: "Context manager",
+						Code:        "with open(path) as f:\n    data = f.read()\n",
+					},
+				},
+			},
+			expected: "## TestRule\n\n### Context manager\n\n```python\nwith open(path) as f:\n    data = f.read()\n```\n",
+		},
+		{
+			name: "example language overrides rule language",
+			rule: Rule{
+				Name:     "TestRule",
+				Language: "go",
+				Examples: []Example{
+					{
+						Description: "Query",
+						Code:        "SELECT id FROM users WHERE id = $1",
+						Language:    "SQL",
+					},
+					{
+						Description: "Call",
+						Code:        "row := db.QueryRowContext(ctx, query, id)",
+					},
+				},
+			},
+			expected: "## TestRule\n\n### Query\n\n```sql\nSELECT id FROM users WHERE id = $1\n```\n" +
+				"\n### Call\n\n```go\nrow := db.QueryRowContext(ctx, query, id)\n```\n",
+		},
+		{
+			name: "untagged example language is detected",
+			rule: Rule{
+				Name: "TestRule",
+				Examples: []Example{
+					{
+						Description: "Handler",
+						Code:        "def handle(self, request):\n    return self.render(request)\n",
+					},
+					{
+						Description: "Prose",
+						Code:        "Keep functions short",
+					},
+				},
+			},
+			expected: "## TestRule\n\n### Handler\n\n```python\ndef handle(self, request):\n    return self.render(request)\n```\n" +
+				"\n### Prose\n\n```\nKeep functions short\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.rule.FormatMarkdown()
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestMatchesCategory(t *testing.T) {
+	tests := []struct {
+		category  string
+		requested string
+		expected  bool
+	}{
+		{category: "code", requested: "code", expected: true},
+		{category: "code/concurrency", requested: "code", expected: true},
+		{category: "code/errors/sentinel", requested: "code/errors", expected: true},
+		{category: "code", requested: "code/errors", expected: false},
+		{category: "codegen", requested: "code", expected: false},
+		{category: "testing/table", requested: AllCategories, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category+" "+tt.requested, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MatchesCategory(tt.category, tt.requested))
+		})
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	rules := []Rule{
+		{Name: "single_exit", ConflictsWith: []string{"early_return"}},
+		{Name: "early_return", ConflictsWith: []string{"single_exit", "not_returned"}},
+		{Name: "named_results", ConflictsWith: []string{"named_results"}},
+		{Name: "bare_returns", ConflictsWith: []string{"early_return"}},
+	}
+
+	assert.Equal(t, [][2]string{
+		{"bare_returns", "early_return"},
+		{"early_return", "single_exit"},
+	}, Conflicts(rules))
+	assert.Empty(t, Conflicts(rules[2:3]))
+}
+
+func TestExample_IsAntiPattern(t *testing.T) {
+	tests := []struct {
+		metadata map[string]any
+		name     string
+		expected bool
+	}{
+		{name: "no metadata", metadata: nil, expected: false},
+		{name: "good true", metadata: map[string]any{MetadataGood: true}, expected: false},
+		{name: "good false", metadata: map[string]any{MetadataGood: false}, expected: true},
+		{name: "good false string", metadata: map[string]any{MetadataGood: "false"}, expected: true},
+		{name: "good invalid string", metadata: map[string]any{MetadataGood: "maybe"}, expected: false},
+		{name: "good numeric", metadata: map[string]any{MetadataGood: 0}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ex := Example{Metadata: tt.metadata}
+			assert.Equal(t, tt.expected, ex.IsAntiPattern())
+		})
+	}
+}
+
 func TestRule_String(t *testing.T) {
 	rule := Rule{
 		Name:        "TestRule",
@@ -100,12 +460,748 @@ func TestService_GetCodeStyle(t *testing.T) {
 
 	mockRepo := NewMockResourceRepo(t)
 	mockRepo.EXPECT().
-		GetCodeStyle(ctx, categories).
+		GetCodeStyle(ctx, categories, Filter{}).
 		Return(expectedRules, nil)
 
 	svc := New(mockRepo)
-	rules, err := svc.GetCodeStyle(ctx, categories)
+	rules, err := svc.GetCodeStyle(ctx, categories, Filter{})
+
+	require.NoError(t, err)
+	// Rules are returned in canonical order: by category, then name
+	assert.Equal(t, []Rule{expectedRules[1], expectedRules[0]}, rules)
+}
+
+func TestService_SetRepo(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{"testing"}
+
+	oldRepo := NewMockResourceRepo(t)
+	newRepo := NewMockResourceRepo(t)
+
+	expectedRules := []Rule{{Name: "NewRule", Category: "testing"}}
+	newRepo.EXPECT().
+		GetCodeStyle(ctx, categories, Filter{}).
+		Return(expectedRules, nil)
+
+	svc := New(oldRepo)
+	svc.SetRepo(newRepo)
+
+	rules, err := svc.GetCodeStyle(ctx, categories, Filter{})
 
 	require.NoError(t, err)
 	assert.Equal(t, expectedRules, rules)
 }
+
+func TestService_GetCodeStyle_PerCategoryLimit(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{"testing", "code"}
+
+	repoRules := []Rule{
+		{Name: "Test1", Category: "testing"},
+		{Name: "Code1", Category: "code"},
+		{Name: "Test2", Category: "testing"},
+		{Name: "Code2", Category: "code"},
+		{Name: "Test3", Category: "testing"},
+	}
+
+	tests := []struct {
+		name     string
+		expected []string
+		limit    int
+	}{
+		{
+			name:     "unlimited",
+			limit:    0,
+			expected: []string{"Code1", "Code2", "Test1", "Test2", "Test3"},
+		},
+		{
+			name:     "one per category",
+			limit:    1,
+			expected: []string{"Code1", "Test1"},
+		},
+		{
+			name:     "two per category",
+			limit:    2,
+			expected: []string{"Code1", "Code2", "Test1", "Test2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := Filter{PerCategoryLimit: tt.limit}
+
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().
+				GetCodeStyle(ctx, categories, filter).
+				Return(repoRules, nil)
+
+			rules, err := New(mockRepo).GetCodeStyle(ctx, categories, filter)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestService_GetCodeStyle_Error(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().
+		GetCodeStyle(ctx, []string{"testing"}, Filter{}).
+		Return(nil, assert.AnError)
+
+	rules, err := New(mockRepo).GetCodeStyle(ctx, []string{"testing"}, Filter{})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, rules)
+}
+
+func TestService_GetCodeStyle_MinSeverity(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{"code"}
+
+	repoRules := []Rule{
+		{Name: "Must", Category: "code", Severity: SeverityMust},
+		{Name: "Should", Category: "code", Severity: SeverityShould},
+		{Name: "May", Category: "code", Severity: SeverityMay},
+		{Name: "Default", Category: "code"},
+	}
+
+	tests := []struct {
+		name        string
+		minSeverity string
+		expected    []string
+	}{
+		{
+			name:        "no threshold",
+			minSeverity: "",
+			expected:    []string{"Default", "May", "Must", "Should"},
+		},
+		{
+			name:        "must only",
+			minSeverity: SeverityMust,
+			expected:    []string{"Must"},
+		},
+		{
+			name:        "should and above",
+			minSeverity: SeverityShould,
+			expected:    []string{"Default", "Must", "Should"},
+		},
+		{
+			name:        "may and above",
+			minSeverity: SeverityMay,
+			expected:    []string{"Default", "May", "Must", "Should"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := Filter{MinSeverity: tt.minSeverity}
+
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().
+				GetCodeStyle(ctx, categories, filter).
+				Return(repoRules, nil)
+
+			rules, err := New(mockRepo).GetCodeStyle(ctx, categories, filter)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestService_Stats(t *testing.T) {
+	ctx := context.Background()
+
+	expected := RepoStats{
+		TotalRules:        2,
+		RulesPerCategory:  map[string]int{"code": 2},
+		RulesWithExamples: 1,
+	}
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().Stats(ctx).Return(expected, nil)
+
+	stats, err := New(mockRepo).Stats(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, stats)
+}
+
+func TestService_Count(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().Count(ctx).Return(3, nil)
+
+	count, err := New(mockRepo).Count(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestService_Diagnose(t *testing.T) {
+	ctx := context.Background()
+
+	expected := Diagnostics{Type: "static", Rules: 2, Categories: []string{"code"}}
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().Diagnose(ctx).Return(expected)
+
+	assert.Equal(t, expected, New(mockRepo).Diagnose(ctx))
+}
+
+func TestService_GetByNames(t *testing.T) {
+	ctx := context.Background()
+	names := []string{"b_rule", "stale", "a_rule", "b_rule"}
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().GetByNames(ctx, names).Return([]Rule{
+		{Name: "a_rule", Category: "testing"},
+		{Name: "b_rule", Category: "code"},
+		{Name: "a_rule", Category: "code"},
+	}, nil)
+
+	rules, missing, err := New(mockRepo).GetByNames(ctx, names)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Name: "b_rule", Category: "code"},
+		{Name: "a_rule", Category: "code"},
+		{Name: "a_rule", Category: "testing"},
+	}, rules)
+	assert.Equal(t, []string{"stale"}, missing)
+}
+
+func TestService_GetByNames_Error(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().GetByNames(ctx, []string{"rule"}).Return(nil, assert.AnError)
+
+	rules, missing, err := New(mockRepo).GetByNames(ctx, []string{"rule"})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, rules)
+	assert.Nil(t, missing)
+}
+
+func TestService_GetCodeStyle_UpdatedAt(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{"code"}
+
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	repoRules := []Rule{
+		{Name: "a_unknown", Category: "code"},
+		{Name: "b_old", Category: "code", UpdatedAt: jan},
+		{Name: "c_new", Category: "code", UpdatedAt: feb},
+	}
+
+	tests := []struct {
+		name     string
+		expected []string
+		filter   Filter
+	}{
+		{
+			name:     "canonical order by default",
+			filter:   Filter{},
+			expected: []string{"a_unknown", "b_old", "c_new"},
+		},
+		{
+			name:     "sorted by update time with unknown last",
+			filter:   Filter{SortBy: SortUpdated},
+			expected: []string{"c_new", "b_old", "a_unknown"},
+		},
+		{
+			name:     "updated since is inclusive and excludes unknown",
+			filter:   Filter{UpdatedSince: jan},
+			expected: []string{"b_old", "c_new"},
+		},
+		{
+			name:     "sorted by update time with limit keeps most recent",
+			filter:   Filter{SortBy: SortUpdated, PerCategoryLimit: 1},
+			expected: []string{"c_new"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().
+				GetCodeStyle(ctx, categories, tt.filter).
+				Return(repoRules, nil)
+
+			rules, err := New(mockRepo).GetCodeStyle(ctx, categories, tt.filter)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestService_GetCodeStyle_SortBy(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{AllCategories}
+
+	repoRules := []Rule{
+		{Name: "naming", Category: "code", Severity: SeverityMay},
+		{Name: "table_tests", Category: "testing", Severity: SeverityMust, Order: 2},
+		{Name: "errors", Category: "code", Order: 1},
+		{Name: "godoc", Category: "documentation", Severity: SeverityMust},
+		{Name: "benchmarks", Category: "testing", Order: 2},
+	}
+
+	tests := []struct {
+		weights  map[string]int
+		name     string
+		sortBy   string
+		expected []string
+	}{
+		{
+			name:     "canonical",
+			sortBy:   SortCanonical,
+			expected: []string{"errors", "naming", "godoc", "benchmarks", "table_tests"},
+		},
+		{
+			name:     "category weights",
+			weights:  map[string]int{"testing": 3, "documentation": 2},
+			expected: []string{"benchmarks", "table_tests", "godoc", "errors", "naming"},
+		},
+		{
+			name:     "category weights with severity ties",
+			sortBy:   SortSeverity,
+			weights:  map[string]int{"code": 2},
+			expected: []string{"errors", "naming", "godoc", "table_tests", "benchmarks"},
+		},
+		{
+			name:     "severity",
+			sortBy:   SortSeverity,
+			expected: []string{"godoc", "table_tests", "errors", "benchmarks", "naming"},
+		},
+		{
+			name:     "name",
+			sortBy:   SortName,
+			expected: []string{"benchmarks", "errors", "godoc", "naming", "table_tests"},
+		},
+		{
+			name:     "manual with ties in repository order",
+			sortBy:   SortManual,
+			expected: []string{"errors", "table_tests", "benchmarks", "naming", "godoc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := Filter{SortBy: tt.sortBy, CategoryWeights: tt.weights}
+
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().GetCodeStyle(ctx, categories, filter).Return(repoRules, nil)
+
+			rules, err := New(mockRepo).GetCodeStyle(ctx, categories, filter)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestCategoryWeight(t *testing.T) {
+	weights := map[string]int{"code": 2, "code/concurrency": 4}
+
+	assert.Equal(t, 2, categoryWeight("code", weights))
+	assert.Equal(t, 4, categoryWeight("code/concurrency/locks", weights))
+	assert.Equal(t, 2, categoryWeight("code/errors", weights))
+	assert.Equal(t, 1, categoryWeight("testing", weights))
+}
+
+func TestIsValidSort(t *testing.T) {
+	for _, sortBy := range []string{SortCanonical, SortUpdated, SortSeverity, SortName, SortManual, SortPopularity} {
+		assert.True(t, IsValidSort(sortBy), sortBy)
+	}
+
+	assert.False(t, IsValidSort("priority"))
+}
+
+func TestService_GetCodeStyle_MaxExamplesPerRule(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{"code"}
+
+	examples := []Example{
+		{Description: "plain", Code: "return err"},
+		{Description: "wrapped", Code: `return fmt.Errorf("read: %w", err)`},
+		{Description: "joined", Code: "return errors.Join(a, b)"},
+		{Description: "wrapped again", Code: `return fmt.Errorf("write: %w", err)`},
+	}
+
+	repoRules := []Rule{
+		{Name: "errors", Category: "code", Examples: examples},
+		{Name: "short", Category: "code", Examples: examples[:1]},
+	}
+
+	tests := []struct {
+		name     string
+		expected []string
+		filter   Filter
+	}{
+		{
+			name:     "first examples without code_contains",
+			filter:   Filter{MaxExamplesPerRule: 2},
+			expected: []string{"plain", "wrapped"},
+		},
+		{
+			name:     "matching examples preferred in original order",
+			filter:   Filter{MaxExamplesPerRule: 2, CodeContains: "ERRORF"},
+			expected: []string{"wrapped", "wrapped again"},
+		},
+		{
+			name:     "matching examples topped up with others",
+			filter:   Filter{MaxExamplesPerRule: 3, CodeContains: "join"},
+			expected: []string{"plain", "wrapped", "joined"},
+		},
+		{
+			name:     "unlimited",
+			filter:   Filter{},
+			expected: []string{"plain", "wrapped", "joined", "wrapped again"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().GetCodeStyle(ctx, categories, tt.filter).Return(repoRules, nil)
+
+			rules, err := New(mockRepo).GetCodeStyle(ctx, categories, tt.filter)
+			require.NoError(t, err)
+			require.Len(t, rules, 2)
+
+			descriptions := make([]string, 0, len(rules[0].Examples))
+			for _, ex := range rules[0].Examples {
+				descriptions = append(descriptions, ex.Description)
+			}
+
+			assert.Equal(t, tt.expected, descriptions)
+			assert.Len(t, rules[1].Examples, 1)
+			assert.Len(t, repoRules[0].Examples, 4, "repository rules must not be modified")
+		})
+	}
+}
+
+func TestService_GetCodeStyle_ExampleOrder(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{"code"}
+
+	repoRules := []Rule{
+		{
+			Name:     "errors",
+			Category: "code",
+			Examples: []Example{
+				{Description: "plain", Code: "return err"},
+				{Description: "joined", Code: "return errors.Join(a, b)", Order: 2},
+				{Description: "unordered", Code: "panic(err)"},
+				{Description: "wrapped", Code: `return fmt.Errorf("read: %w", err)`, Order: 1},
+				{Description: "joined again", Code: "return errors.Join(c, d)", Order: 2},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		expected []string
+		filter   Filter
+	}{
+		{
+			name:     "ordered examples first, ties and unordered in config order",
+			filter:   Filter{},
+			expected: []string{"wrapped", "joined", "joined again", "plain", "unordered"},
+		},
+		{
+			name:     "limit keeps the first ordered examples",
+			filter:   Filter{MaxExamplesPerRule: 2},
+			expected: []string{"wrapped", "joined"},
+		},
+		{
+			name:     "limit prefers matching examples in sorted order",
+			filter:   Filter{MaxExamplesPerRule: 2, CodeContains: "err)"},
+			expected: []string{"wrapped", "unordered"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().GetCodeStyle(ctx, categories, tt.filter).Return(repoRules, nil)
+
+			rules, err := New(mockRepo).GetCodeStyle(ctx, categories, tt.filter)
+			require.NoError(t, err)
+			require.Len(t, rules, 1)
+
+			descriptions := make([]string, 0, len(rules[0].Examples))
+			for _, ex := range rules[0].Examples {
+				descriptions = append(descriptions, ex.Description)
+			}
+
+			assert.Equal(t, tt.expected, descriptions)
+			assert.Equal(t, "plain", repoRules[0].Examples[0].Description, "repository rules must not be modified")
+		})
+	}
+}
+
+func TestService_GetByNames_ExampleOrder(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().GetByNames(ctx, []string{"errors"}).Return([]Rule{
+		{Name: "errors", Category: "code", Examples: []Example{{Description: "second", Order: 2}, {Description: "first", Order: 1}}},
+	}, nil)
+
+	rules, _, err := New(mockRepo).GetByNames(ctx, []string{"errors"})
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Len(t, rules[0].Examples, 2)
+
+	assert.Equal(t, "first", rules[0].Examples[0].Description)
+	assert.Equal(t, "second", rules[0].Examples[1].Description)
+}
+
+func TestService_GetCodeStyle_CanonicalOrder(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{"testing", "code"}
+
+	repoRules := []Rule{
+		{Name: "b", Category: "testing"},
+		{Name: "a", Category: "testing"},
+		{Name: "z", Category: "code"},
+		{Name: "a", Category: "code"},
+	}
+
+	mockRepo := NewMockResourceRepo(t)
+	mockRepo.EXPECT().
+		GetCodeStyle(ctx, categories, Filter{}).
+		Return(repoRules, nil)
+
+	rules, err := New(mockRepo).GetCodeStyle(ctx, categories, Filter{})
+	require.NoError(t, err)
+
+	expected := []Rule{
+		{Name: "a", Category: "code"},
+		{Name: "z", Category: "code"},
+		{Name: "a", Category: "testing"},
+		{Name: "b", Category: "testing"},
+	}
+
+	assert.Equal(t, expected, rules)
+	assert.Equal(t, "b", repoRules[0].Name, "repository slice must not be reordered")
+}
+
+func TestService_GetCodeStyle_Deprecated(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{"code"}
+
+	repoRules := []Rule{
+		{Name: "Active", Category: "code"},
+		{Name: "Old", Category: "code", Deprecated: true},
+	}
+
+	tests := []struct {
+		name              string
+		expected          []string
+		includeDeprecated bool
+	}{
+		{
+			name:              "excluded by default",
+			includeDeprecated: false,
+			expected:          []string{"Active"},
+		},
+		{
+			name:              "explicitly included",
+			includeDeprecated: true,
+			expected:          []string{"Active", "Old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := Filter{IncludeDeprecated: tt.includeDeprecated}
+
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().
+				GetCodeStyle(ctx, categories, filter).
+				Return(repoRules, nil)
+
+			rules, err := New(mockRepo).GetCodeStyle(ctx, categories, filter)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestService_GetCodeStyle_ExcludeCategories(t *testing.T) {
+	ctx := context.Background()
+
+	all := []Rule{
+		{Name: "Concurrency", Category: "code/concurrency"},
+		{Name: "Docs", Category: "documentation"},
+		{Name: "Naming", Category: "code"},
+		{Name: "Tables", Category: "testing"},
+	}
+
+	tests := []struct {
+		name       string
+		categories []string
+		exclude    []string
+		expected   []string
+	}{
+		{
+			name:       "no exclusion",
+			categories: []string{AllCategories},
+			expected:   []string{"Naming", "Concurrency", "Docs", "Tables"},
+		},
+		{
+			name:       "all except one category",
+			categories: []string{AllCategories},
+			exclude:    []string{"documentation"},
+			expected:   []string{"Naming", "Concurrency", "Tables"},
+		},
+		{
+			name:       "nested category of an included parent",
+			categories: []string{"code"},
+			exclude:    []string{"code/concurrency"},
+			expected:   []string{"Naming"},
+		},
+		{
+			name:       "parent of an included nested category",
+			categories: []string{"code/concurrency", "testing"},
+			exclude:    []string{"code"},
+			expected:   []string{"Tables"},
+		},
+		{
+			name:       "exclude takes precedence over the same include",
+			categories: []string{"code", "testing"},
+			exclude:    []string{"testing"},
+			expected:   []string{"Naming", "Concurrency"},
+		},
+		{
+			name:       "category not requested",
+			categories: []string{"testing"},
+			exclude:    []string{"documentation"},
+			expected:   []string{"Tables"},
+		},
+		{
+			name:       "everything excluded",
+			categories: []string{"code", "documentation"},
+			exclude:    []string{AllCategories},
+			expected:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := Filter{ExcludeCategories: tt.exclude}
+
+			var repoRules []Rule
+
+			for _, rule := range all {
+				for _, cat := range tt.categories {
+					if MatchesCategory(rule.Category, cat) {
+						repoRules = append(repoRules, rule)
+						break
+					}
+				}
+			}
+
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().
+				GetCodeStyle(ctx, tt.categories, filter).
+				Return(repoRules, nil)
+
+			rules, err := New(mockRepo).GetCodeStyle(ctx, tt.categories, filter)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestService_GetCodeStyle_WithExamplesOnly(t *testing.T) {
+	ctx := context.Background()
+	categories := []string{"code"}
+
+	repoRules := []Rule{
+		{Name: "WithExample", Category: "code", Examples: []Example{{Code: "x := 1"}}},
+		{Name: "WithoutExamples", Category: "code"},
+		{Name: "EmptyExamples", Category: "code", Examples: []Example{}},
+	}
+
+	tests := []struct {
+		name             string
+		expected         []string
+		withExamplesOnly bool
+	}{
+		{
+			name:             "all rules by default",
+			withExamplesOnly: false,
+			expected:         []string{"EmptyExamples", "WithExample", "WithoutExamples"},
+		},
+		{
+			name:             "only rules with examples",
+			withExamplesOnly: true,
+			expected:         []string{"WithExample"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := Filter{WithExamplesOnly: tt.withExamplesOnly}
+
+			mockRepo := NewMockResourceRepo(t)
+			mockRepo.EXPECT().
+				GetCodeStyle(ctx, categories, filter).
+				Return(repoRules, nil)
+
+			rules, err := New(mockRepo).GetCodeStyle(ctx, categories, filter)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
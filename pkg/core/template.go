@@ -0,0 +1,39 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ErrNoTemplate is returned by Rule.RenderTemplate for rules without a template.
+var ErrNoTemplate = errors.New("rule has no template")
+
+// ParseTemplate parses a rule template written in text/template syntax.
+// Executing the template fails on references to variables that are not provided.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(text)
+}
+
+// RenderTemplate renders the rule template with vars, which are available in the
+// template as {{.name}}.
+// Returns ErrNoTemplate if the rule has no template, or an error describing why
+// the template could not be parsed or executed, e.g. a missing variable.
+func (r *Rule) RenderTemplate(vars map[string]string) (string, error) {
+	if r.Template == "" {
+		return "", fmt.Errorf("%w: %s", ErrNoTemplate, r.Name)
+	}
+
+	tmpl, err := ParseTemplate(r.Name, r.Template)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, vars); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRule_RenderTemplate(t *testing.T) {
+	tests := []struct {
+		vars     map[string]string
+		wantErr  error
+		name     string
+		template string
+		expected string
+		errText  string
+	}{
+		{
+			name:     "variables substituted",
+			template: "type {{.name}} struct {\n\t{{.field}} string\n}\n",
+			vars:     map[string]string{"name": "User", "field": "Email"},
+			expected: "type User struct {\n\tEmail string\n}\n",
+		},
+		{
+			name:     "template without variables",
+			template: "package main\n",
+			expected: "package main\n",
+		},
+		{
+			name:    "no template",
+			wantErr: ErrNoTemplate,
+		},
+		{
+			name:     "missing variable",
+			template: "type {{.name}} struct{}",
+			vars:     map[string]string{"other": "x"},
+			errText:  `map has no entry for key "name"`,
+		},
+		{
+			name:     "invalid template",
+			template: "type {{.name struct{}",
+			errText:  "parse template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := Rule{Name: "scaffold", Template: tt.template}
+
+			result, err := rule.RenderTemplate(tt.vars)
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.errText != "":
+				assert.ErrorContains(t, err, tt.errText)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package core
+
+import (
+	"context"
+)
+
+// Warmer is implemented by repositories that initialize lazily, such as connections to
+// external storage or indexes, so that they can be primed before the first request.
+type Warmer interface {
+	// Warmup primes the repository, e.g. by issuing a trivial query.
+	Warmup(ctx context.Context) error
+}
+
+// Warmup primes the current repository so that the first request does not pay for its
+// lazy initialization. Repositories implementing Warmer are warmed up by it; others are
+// sent a trivial Count query.
+// Returns error if the repository access fails.
+func (s *Service) Warmup(ctx context.Context) error {
+	resource := s.repo()
+
+	if warmer, ok := resource.(Warmer); ok {
+		return warmer.Warmup(ctx)
+	}
+
+	_, err := resource.Count(ctx)
+
+	return err
+}
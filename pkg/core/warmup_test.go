@@ -0,0 +1,45 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// warmRepo is a repository implementing Warmer.
+type warmRepo struct {
+	*MockResourceRepo
+	err    error
+	warmed int
+}
+
+func (r *warmRepo) Warmup(_ context.Context) error {
+	r.warmed++
+	return r.err
+}
+
+func TestService_Warmup(t *testing.T) {
+	t.Run("warmer", func(t *testing.T) {
+		repo := &warmRepo{MockResourceRepo: NewMockResourceRepo(t)}
+
+		assert.NoError(t, New(repo).Warmup(context.Background()))
+		assert.Equal(t, 1, repo.warmed)
+
+		repo.err = assert.AnError
+
+		assert.ErrorIs(t, New(repo).Warmup(context.Background()), assert.AnError)
+	})
+
+	t.Run("trivial query", func(t *testing.T) {
+		repo := NewMockResourceRepo(t)
+		repo.EXPECT().Count(mock.Anything).Return(3, nil).Once()
+		repo.EXPECT().Count(mock.Anything).Return(0, assert.AnError).Once()
+
+		svc := New(repo)
+
+		assert.NoError(t, svc.Warmup(context.Background()))
+		assert.ErrorIs(t, svc.Warmup(context.Background()), assert.AnError)
+	})
+}
@@ -0,0 +1,56 @@
+package repo_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/ksysoev/mcp-go-tools/pkg/repo"
+)
+
+// sliceRepo is a minimal repository backed by a slice, standing in for external storage.
+type sliceRepo struct {
+	rules []core.Rule
+}
+
+func (s *sliceRepo) GetCodeStyle(context.Context, []string, core.Filter) ([]core.Rule, error) {
+	return s.rules, nil
+}
+
+func (s *sliceRepo) GetByNames(context.Context, []string) ([]core.Rule, error) {
+	return s.rules, nil
+}
+
+func (s *sliceRepo) Stats(context.Context) (core.RepoStats, error) {
+	return core.RepoStats{TotalRules: len(s.rules)}, nil
+}
+
+func (s *sliceRepo) Count(context.Context) (int, error) {
+	return len(s.rules), nil
+}
+
+func (s *sliceRepo) Fingerprint(context.Context) (string, error) {
+	return core.FingerprintRules(s.rules)
+}
+
+func (s *sliceRepo) Diagnose(context.Context) core.Diagnostics {
+	return core.Diagnostics{Type: "slice", Rules: len(s.rules)}
+}
+
+func ExampleRegister() {
+	// Usually called from the init function of the package providing the repository
+	repo.Register("slice", func(_ context.Context, cfg map[string]any) (core.ResourceRepo, error) {
+		return &sliceRepo{rules: []core.Rule{{Name: fmt.Sprint(cfg["rule"]), Category: "code"}}}, nil
+	})
+
+	// Selected with repository.type: slice; cfg holds the settings under the repository key
+	resource, err := repo.New(context.Background(), "slice", map[string]any{"type": "slice", "rule": "error_wrapping"})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	rules, _ := resource.GetCodeStyle(context.Background(), []string{core.AllCategories}, core.Filter{})
+	fmt.Println(rules[0].Name)
+	// Output: error_wrapping
+}
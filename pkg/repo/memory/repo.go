@@ -0,0 +1,220 @@
+// Package memory provides an in-memory implementation of the code generation rule repository.
+//
+// The rules are held in a slice that can be changed at runtime with AddRule and
+// ReplaceRules, which makes the repository suited to tests and quick prototyping.
+// Rules use the static repository format, checks, matching and conversion, but none of
+// the static.Options: examples are served untrimmed and unlimited, every client gets the
+// same rules, and no lint problems are reported. Importing the package registers the
+// repository under the memory type, selected with repository.type: memory. It is seeded
+// with the rules under repository.rules only; the top-level rules and categories sections
+// are not used.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/ksysoev/mcp-go-tools/pkg/repo"
+	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
+)
+
+// Type is the repository type name the memory repository is registered under.
+const Type = "memory"
+
+func init() {
+	repo.Register(Type, newFromSettings)
+}
+
+// Repository implements core.ResourceRepo over a mutable slice of rules.
+// It is safe for concurrent use.
+type Repository struct {
+	rules static.Config
+	mu    sync.RWMutex
+}
+
+// New creates a memory repository serving rules.
+// Returns error if the rules are rejected by static.CheckRules.
+func New(rules ...static.Rule) (*Repository, error) {
+	r := &Repository{}
+
+	if err := r.ReplaceRules(rules); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// newFromSettings creates a memory repository from its settings, seeding it with the
+// rules under the rules key, which use the same format as the rules of the config file.
+// Returns error if the rules cannot be decoded or are invalid.
+func newFromSettings(_ context.Context, cfg map[string]any) (core.ResourceRepo, error) {
+	var rules static.Config
+
+	if err := mapstructure.Decode(cfg["rules"], &rules); err != nil {
+		return nil, fmt.Errorf("decode rules: %w", err)
+	}
+
+	return New(rules...)
+}
+
+// AddRule appends rule to the served rules.
+// Returns error if the rules including rule are rejected by static.CheckRules,
+// in which case the served rules are left unchanged.
+func (r *Repository) AddRule(rule static.Rule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rules := append(slices.Clip(r.rules), rule)
+	if err := static.CheckRules(&rules); err != nil {
+		return err
+	}
+
+	r.rules = rules
+
+	return nil
+}
+
+// ReplaceRules replaces the served rules with a copy of rules.
+// Returns error if rules are rejected by static.CheckRules,
+// in which case the served rules are left unchanged.
+func (r *Repository) ReplaceRules(rules static.Config) error {
+	rules = slices.Clone(rules)
+	if err := static.CheckRules(&rules); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules = rules
+
+	return nil
+}
+
+// enabled returns a snapshot of the enabled rules, unaffected by later changes.
+func (r *Repository) enabled() static.Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rules := make(static.Config, 0, len(r.rules))
+
+	for _, rule := range r.rules {
+		if rule.IsEnabled() {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// GetCodeStyle returns all rules that match the specified categories and filter,
+// see static.Matches.
+// Returns error if the context is cancelled.
+func (r *Repository) GetCodeStyle(ctx context.Context, categories []string, filter core.Filter) ([]core.Rule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var rules []core.Rule
+
+	for _, rule := range r.enabled() {
+		if static.Matches(rule, categories, filter) {
+			rules = append(rules, static.ConvertRule(rule))
+		}
+	}
+
+	return rules, nil
+}
+
+// GetByNames returns all rules whose name is one of names, in insertion order.
+// Names that match no rule are skipped.
+// Returns error if the context is cancelled.
+func (r *Repository) GetByNames(ctx context.Context, names []string) ([]core.Rule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var rules []core.Rule
+
+	for _, rule := range r.enabled() {
+		if slices.Contains(names, rule.Name) {
+			rules = append(rules, static.ConvertRule(rule))
+		}
+	}
+
+	return rules, nil
+}
+
+// Stats returns aggregate metrics about the enabled rules.
+// Returns error if the context is cancelled.
+func (r *Repository) Stats(ctx context.Context) (core.RepoStats, error) {
+	if err := ctx.Err(); err != nil {
+		return core.RepoStats{}, err
+	}
+
+	rules := r.enabled()
+
+	stats := core.RepoStats{
+		TotalRules:       len(rules),
+		RulesPerCategory: make(map[string]int),
+	}
+
+	for _, rule := range rules {
+		stats.RulesPerCategory[rule.Category]++
+
+		if len(rule.Examples) > 0 {
+			stats.RulesWithExamples++
+		}
+	}
+
+	return stats, nil
+}
+
+// Count returns the number of enabled rules.
+// Returns error if the context is cancelled.
+func (r *Repository) Count(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return len(r.enabled()), nil
+}
+
+// Fingerprint returns the fingerprint of the enabled rules, see core.FingerprintRules.
+// Returns error if the context is cancelled.
+func (r *Repository) Fingerprint(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	rules := r.enabled()
+
+	converted := make([]core.Rule, 0, len(rules))
+	for _, rule := range rules {
+		converted = append(converted, static.ConvertRule(rule))
+	}
+
+	return core.FingerprintRules(converted)
+}
+
+// Diagnose returns a snapshot of the enabled rules.
+func (r *Repository) Diagnose(_ context.Context) core.Diagnostics {
+	rules := r.enabled()
+
+	categories := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		categories = append(categories, rule.Category)
+	}
+
+	slices.Sort(categories)
+
+	return core.Diagnostics{
+		Type:       Type,
+		Rules:      len(rules),
+		Categories: slices.Compact(categories),
+	}
+}
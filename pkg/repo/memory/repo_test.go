@@ -0,0 +1,208 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+	"github.com/ksysoev/mcp-go-tools/pkg/repo"
+	"github.com/ksysoev/mcp-go-tools/pkg/repo/static"
+)
+
+func TestGetCodeStyle(t *testing.T) {
+	disabled := false
+
+	r, err := New(
+		static.Rule{Name: "table_tests", Category: "testing", Severity: "MUST"},
+		static.Rule{Name: "error_wrapping", Category: "code", Examples: []static.Example{{Code: "fmt.Errorf(\"read: %w\", err)"}}},
+		static.Rule{Name: "mutex_naming", Category: "code/concurrency"},
+		static.Rule{Name: "old_rule", Category: "code", Enabled: &disabled},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	rules, err := r.GetCodeStyle(ctx, []string{"code"}, core.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 2 || rules[0].Name != "error_wrapping" || rules[1].Name != "mutex_naming" {
+		t.Errorf("Expected error_wrapping and mutex_naming, got %v", rules)
+	}
+
+	rules, err = r.GetCodeStyle(ctx, []string{core.AllCategories}, core.Filter{CodeContains: "errorf"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 1 || rules[0].Name != "error_wrapping" {
+		t.Errorf("Expected error_wrapping, got %v", rules)
+	}
+
+	rules, err = r.GetCodeStyle(ctx, []string{"testing"}, core.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 1 || rules[0].Severity != core.SeverityMust {
+		t.Errorf("Expected table_tests converted with severity %q, got %v", core.SeverityMust, rules)
+	}
+}
+
+func TestAddRule(t *testing.T) {
+	r, err := New()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if err := r.AddRule(static.Rule{Name: "error_wrapping", Category: "code"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := r.AddRule(static.Rule{Name: "panics", Category: "code", ConflictsWith: []string{"error_wrapping"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err = r.AddRule(static.Rule{Name: "bad_severity", Category: "code", Severity: "always"})
+	if !errors.Is(err, static.ErrInvalidSeverity) {
+		t.Errorf("Expected ErrInvalidSeverity, got %v", err)
+	}
+
+	count, err := r.Count(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("Expected 2 rules after rejected rule, got %d", count)
+	}
+
+	rules, err := r.GetByNames(ctx, []string{"panics", "missing"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 1 || rules[0].Name != "panics" {
+		t.Errorf("Expected panics, got %v", rules)
+	}
+}
+
+func TestReplaceRules(t *testing.T) {
+	r, err := New(static.Rule{Name: "error_wrapping", Category: "code"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	before, err := r.Fingerprint(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err = r.ReplaceRules(static.Config{{Name: "panics", Category: "code", ConflictsWith: []string{"missing"}}})
+	if !errors.Is(err, static.ErrUnknownConflict) {
+		t.Errorf("Expected ErrUnknownConflict, got %v", err)
+	}
+
+	rules := static.Config{
+		{Name: "table_tests", Category: "testing", Examples: []static.Example{{Code: "tests := []struct{}{}"}}},
+		{Name: "godoc", Category: "documentation"},
+	}
+
+	if err := r.ReplaceRules(rules); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules[0].Name = "changed"
+
+	stats, err := r.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if stats.TotalRules != 2 || stats.RulesWithExamples != 1 || stats.RulesPerCategory["testing"] != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	found, err := r.GetByNames(ctx, []string{"table_tests"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(found) != 1 {
+		t.Errorf("Expected replaced rules to be copied, got %v", found)
+	}
+
+	after, err := r.Fingerprint(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if after == before {
+		t.Error("Expected fingerprint to change after replacing rules")
+	}
+
+	diag := r.Diagnose(ctx)
+	if diag.Type != Type || diag.Rules != 2 || len(diag.Categories) != 2 {
+		t.Errorf("Unexpected diagnostics: %+v", diag)
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	r, err := New(static.Rule{Name: "error_wrapping", Category: "code"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := r.GetCodeStyle(ctx, []string{"code"}, core.Filter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from GetCodeStyle, got %v", err)
+	}
+
+	if _, err := r.GetByNames(ctx, []string{"error_wrapping"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from GetByNames, got %v", err)
+	}
+
+	if _, err := r.Count(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from Count, got %v", err)
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	settings := map[string]any{
+		"type": Type,
+		"rules": []any{
+			map[string]any{"name": "error_wrapping", "category": "code", "severity": "must"},
+			map[string]any{"name": "panics", "category": "code", "conflictswith": []any{"error_wrapping"}},
+		},
+	}
+
+	resource, err := repo.New(context.Background(), Type, settings)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := resource.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 2 || rules[1].ConflictsWith[0] != "error_wrapping" {
+		t.Errorf("Expected rules decoded from settings, got %v", rules)
+	}
+
+	settings["rules"] = []any{map[string]any{"name": "bad", "category": "code", "severity": "always"}}
+
+	if _, err := repo.New(context.Background(), Type, settings); !errors.Is(err, static.ErrInvalidSeverity) {
+		t.Errorf("Expected ErrInvalidSeverity, got %v", err)
+	}
+}
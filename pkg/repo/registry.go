@@ -0,0 +1,98 @@
+// Package repo provides a registry of rule repository implementations.
+//
+// The built-in static repository is configured from the rules in the config
+// file. Other packages can provide repositories backed by external storage,
+// such as a database, by registering a factory under a type name, usually from
+// an init function, and importing the package for its side effects:
+//
+//	func init() {
+//		repo.Register("postgres", newPostgresRepo)
+//	}
+//
+// The factory is then selected with the repository.type config key and receives
+// the settings under the repository key.
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+)
+
+// TypeStatic is the type of the built-in repository serving the rules from the config file.
+const TypeStatic = "static"
+
+// ErrUnknownType is returned by New for repository types without a registered factory.
+var ErrUnknownType = errors.New("unknown repository type")
+
+// Factory creates a repository from its settings, the contents of the repository config key.
+// Factories that load rules from slow storage should stop and return the context error
+// when ctx is cancelled, so that an aborted startup does not wait for them.
+type Factory func(ctx context.Context, cfg map[string]any) (core.ResourceRepo, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a repository factory available under the given type name.
+// It panics if the name is empty or the built-in TypeStatic, if factory is nil,
+// or if Register is called twice with the same name.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	switch {
+	case name == "" || name == TypeStatic:
+		panic(fmt.Sprintf("repo: invalid repository type %q", name))
+	case factory == nil:
+		panic("repo: Register factory is nil for type " + name)
+	case factories[name] != nil:
+		panic("repo: Register called twice for type " + name)
+	}
+
+	factories[name] = factory
+}
+
+// Types returns the sorted names of the registered repository types.
+func Types() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	types := make([]string, 0, len(factories))
+	for name := range factories {
+		types = append(types, name)
+	}
+
+	slices.Sort(types)
+
+	return types
+}
+
+// New creates a repository of a registered type from its settings.
+// Returns ErrUnknownType if no factory is registered under name, the context error
+// if ctx is already cancelled, or the factory error.
+func New(ctx context.Context, name string, cfg map[string]any) (core.ResourceRepo, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownType, name)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resource, err := factory(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create %s repository: %w", name, err)
+	}
+
+	return resource, nil
+}
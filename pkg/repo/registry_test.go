@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/ksysoev/mcp-go-tools/pkg/core"
+)
+
+// fakeRepo serves a fixed set of rules.
+type fakeRepo struct {
+	rules []core.Rule
+}
+
+func (f *fakeRepo) GetCodeStyle(_ context.Context, categories []string, _ core.Filter) ([]core.Rule, error) {
+	var rules []core.Rule
+
+	for _, rule := range f.rules {
+		for _, cat := range categories {
+			if core.MatchesCategory(rule.Category, cat) {
+				rules = append(rules, rule)
+				break
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+func (f *fakeRepo) GetByNames(_ context.Context, names []string) ([]core.Rule, error) {
+	var rules []core.Rule
+
+	for _, rule := range f.rules {
+		if slices.Contains(names, rule.Name) {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+func (f *fakeRepo) Stats(_ context.Context) (core.RepoStats, error) {
+	return core.RepoStats{TotalRules: len(f.rules)}, nil
+}
+
+func (f *fakeRepo) Count(_ context.Context) (int, error) {
+	return len(f.rules), nil
+}
+
+func (f *fakeRepo) Fingerprint(_ context.Context) (string, error) {
+	return core.FingerprintRules(f.rules)
+}
+
+func (f *fakeRepo) Diagnose(_ context.Context) core.Diagnostics {
+	return core.Diagnostics{Type: "fake", Rules: len(f.rules)}
+}
+
+// newFakeRepo creates a fakeRepo with one rule per name listed under the rules setting.
+func newFakeRepo(_ context.Context, cfg map[string]any) (core.ResourceRepo, error) {
+	names, ok := cfg["rules"].([]any)
+	if !ok {
+		return nil, errors.New("rules setting is required")
+	}
+
+	repo := &fakeRepo{}
+	for _, name := range names {
+		repo.rules = append(repo.rules, core.Rule{Name: fmt.Sprint(name), Category: "code"})
+	}
+
+	return repo, nil
+}
+
+func TestRegisterAndNew(t *testing.T) {
+	Register("fake-new", newFakeRepo)
+
+	resource, err := New(context.Background(), "fake-new", map[string]any{"rules": []any{"rule1", "rule2"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	count, err := resource.Count(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("Expected 2 rules, got %d", count)
+	}
+
+	if !slices.Contains(Types(), "fake-new") {
+		t.Errorf("Expected registered type in %v", Types())
+	}
+}
+
+func TestNewFactoryError(t *testing.T) {
+	Register("fake-error", newFakeRepo)
+
+	_, err := New(context.Background(), "fake-error", map[string]any{})
+	if err == nil || err.Error() != "create fake-error repository: rules setting is required" {
+		t.Errorf("Expected factory error, got %v", err)
+	}
+}
+
+func TestNewCancelled(t *testing.T) {
+	started := make(chan struct{})
+
+	Register("fake-slow", func(ctx context.Context, _ map[string]any) (core.ResourceRepo, error) {
+		close(started)
+
+		// Stands in for loading rules one by one from slow storage
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, err := New(ctx, "fake-slow", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error %v, got %v", context.Canceled, err)
+	}
+
+	// A cancelled context is reported before calling the factory
+	Register("fake-unreachable", func(context.Context, map[string]any) (core.ResourceRepo, error) {
+		t.Error("Factory must not be called with a cancelled context")
+		return nil, nil
+	})
+
+	_, err = New(ctx, "fake-unreachable", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestNewUnknownType(t *testing.T) {
+	_, err := New(context.Background(), "unknown", nil)
+	if !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Expected error %v, got %v", ErrUnknownType, err)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	Register("fake-duplicate", newFakeRepo)
+
+	tests := []struct {
+		factory Factory
+		name    string
+		typ     string
+	}{
+		{name: "empty type", typ: "", factory: newFakeRepo},
+		{name: "built-in type", typ: TypeStatic, factory: newFakeRepo},
+		{name: "nil factory", typ: "fake-nil", factory: nil},
+		{name: "duplicate type", typ: "fake-duplicate", factory: newFakeRepo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected Register to panic")
+				}
+			}()
+
+			Register(tt.typ, tt.factory)
+		})
+	}
+}
@@ -6,11 +6,38 @@
 package static
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/ksysoev/mcp-go-tools/pkg/core"
 )
 
+// ErrNoRules is returned by New when the rule set is empty and Options.RequireRules is set.
+var ErrNoRules = errors.New("no rules configured")
+
+// ErrInvalidSeverity is returned by New when a rule declares an unsupported severity.
+var ErrInvalidSeverity = errors.New("invalid severity")
+
+// ErrInvalidUpdatedAt is returned by New when a rule declares an update time that is not RFC3339.
+var ErrInvalidUpdatedAt = errors.New("invalid updatedAt")
+
+// ErrInvalidTemplate is returned by New when a rule template cannot be parsed.
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// ErrUnknownConflict is returned by New when a rule declares a conflict with a rule that does not exist.
+var ErrUnknownConflict = errors.New("conflict with unknown rule")
+
+// ErrUndeclaredCategory is returned by New when categories are declared and a rule uses another one.
+var ErrUndeclaredCategory = errors.New("undeclared category")
+
 // Config represents the main configuration structure for code generation guidelines.
 // It is a slice of Rule that can be loaded from configuration files.
 type Config = []Rule
@@ -18,48 +45,346 @@ type Config = []Rule
 // Rule defines a universal structure for all types of code generation rules.
 // It mirrors core.Rule but uses mapstructure tags for configuration file parsing.
 type Rule struct {
-	Name        string    `mapstructure:"name"`
-	Category    string    `mapstructure:"category"` // One of: "documentation", "testing", "code"
-	Description string    `mapstructure:"description"`
-	Examples    []Example `mapstructure:"examples"`
+	Enabled         *bool     `mapstructure:"enabled"`  // Disabled rules are never served; defaults to true
+	Language        string    `mapstructure:"language"` // Optional language of the examples, e.g. "go" or "python"
+	Description     string    `mapstructure:"description"`
+	Severity        string    `mapstructure:"severity"`        // One of: "must", "should", "may"; defaults to "should"
+	DeprecationNote string    `mapstructure:"deprecationNote"` // Optional reason the rule was deprecated
+	UpdatedAt       string    `mapstructure:"updatedAt"`       // Optional RFC3339 time of the last change
+	Name            string    `mapstructure:"name"`
+	Template        string    `mapstructure:"template"` // Optional text/template scaffolding rendered by the rendertemplate tool
+	Category        string    `mapstructure:"category"` // One of: "documentation", "testing", "code"
+	Examples        []Example `mapstructure:"examples"`
+	ConflictsWith   []string  `mapstructure:"conflictsWith"` // Names of rules giving contradictory advice
+	Order           int       `mapstructure:"order"`         // Optional position in the manual sort order, zero if unset
+	Deprecated      bool      `mapstructure:"deprecated"`
+}
+
+// IsEnabled reports whether the rule should be served. Rules are enabled unless
+// Enabled is explicitly set to false.
+func (r *Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
 }
 
 // Example provides a usage example for a rule.
-// It includes a description of what the example demonstrates
-// and the actual code snippet.
+// It includes a description of what the example demonstrates,
+// the actual code snippet, and optional structured metadata
+// (e.g. good: false to mark an anti-pattern).
 type Example struct {
-	Description string `mapstructure:"description"`
-	Code        string `mapstructure:"code"`
+	Metadata    map[string]any `mapstructure:"metadata"`
+	Description string         `mapstructure:"description"`
+	Code        string         `mapstructure:"code"`
+	Language    string         `mapstructure:"language"` // Overrides the rule language for this example
+	Order       int            `mapstructure:"order"`    // Optional position among the rule examples, zero if unset
+}
+
+// Category documents a rule category declared in the categories config section.
+type Category struct {
+	Name        string `mapstructure:"name"`
+	DisplayName string `mapstructure:"displayName"` // Optional human-readable name, e.g. "Testing"
+	Description string `mapstructure:"description"` // Optional summary of the rules in the category
+}
+
+// Options holds repository settings that are not part of the rule set itself.
+type Options struct {
+	// Clients restricts the rules served to specific clients, keyed by client identifier.
+	// Clients without an entry are served the full rule set.
+	Clients map[string]ClientFilter `mapstructure:"clients"`
+	// TrimExamples removes trailing whitespace from each line of example code and trailing
+	// blank lines, left by YAML block scalars, at load time; defaults to true
+	TrimExamples *bool `mapstructure:"trimExamples"`
+	// Categories declares the rule categories, taken from the top-level categories
+	// config section rather than the repository settings. When set, every rule
+	// must use one of the declared categories or one nested in them, e.g. code/errors
+	// when code is declared.
+	Categories []Category `mapstructure:"-"`
+	// Lint configures advisory warnings about oversized rules logged at load time
+	Lint LintOptions `mapstructure:"lint"`
+	// MaxExampleChars truncates example code longer than this many characters, zero means unlimited
+	MaxExampleChars int `mapstructure:"maxExampleChars"`
+	// RequireRules turns an empty rule set into a startup error instead of a warning
+	RequireRules bool `mapstructure:"requireRules"`
+}
+
+// trimsExamples reports whether example code is trimmed at load time, see TrimExamples.
+func (o *Options) trimsExamples() bool {
+	return o.TrimExamples == nil || *o.TrimExamples
 }
 
+// Default lint thresholds used when LintOptions fields are zero.
+const (
+	defaultMaxExampleBytes = 2048
+	defaultMaxExamples     = 10
+)
+
+// LintOptions holds thresholds for the advisory checks run when rules are loaded.
+// Exceeding a threshold logs a warning naming the rule; it never fails loading.
+// Zero values use the defaults, negative values disable the check.
+type LintOptions struct {
+	// MaxExampleBytes warns about example code larger than this many bytes, 2048 by default
+	MaxExampleBytes int `mapstructure:"maxExampleBytes"`
+	// MaxExamples warns about rules with more examples than this, 10 by default
+	MaxExamples int `mapstructure:"maxExamples"`
+}
+
+// ClientFilter narrows down the rules served to a client.
+// Each non-empty list restricts the rules to its entries; empty lists apply no restriction.
+type ClientFilter struct {
+	// Categories lists the rule categories available to the client, including their descendants
+	Categories []string `mapstructure:"categories"`
+	// Rules lists the rule names available to the client
+	Rules []string `mapstructure:"rules"`
+}
+
+// allows reports whether the rule is available under the filter.
+func (f ClientFilter) allows(rule Rule) bool {
+	if len(f.Categories) > 0 && !matchesAnyCategory(rule.Category, f.Categories) {
+		return false
+	}
+
+	if len(f.Rules) > 0 && !slices.Contains(f.Rules, rule.Name) {
+		return false
+	}
+
+	return true
+}
+
+// truncationMarker is appended to example code cut by Options.MaxExampleChars.
+const truncationMarker = "// ... truncated\n"
+
 // Repository provides functionality to work with static resources and code rules.
 // It implements core.ResourceRepo interface and is safe for concurrent use
 // as it operates on immutable configuration data.
+// All queries only consider the rules available to the client identified by
+// core.ClientIDFromContext, see Options.Clients.
 type Repository struct {
-	config *Config
+	config  *Config
+	clients map[string]ClientFilter
+	lint    LintOptions
 }
 
 // New creates a new instance of the Repository.
 // The provided configuration must be properly initialized and will be used
-// as the source of all rule data.
-func New(cfg *Config) *Repository {
+// as the source of all rule data. A rule set without enabled rules is logged as a warning,
+// or rejected with ErrNoRules when opts.RequireRules is set.
+// Returns ErrInvalidSeverity if a rule declares an unsupported severity, and
+// ErrInvalidUpdatedAt if a rule declares an update time that is not RFC3339,
+// ErrInvalidTemplate if a rule template cannot be parsed, and ErrUnknownConflict
+// if a rule declares a conflict with a rule name that is not configured.
+// When opts.Categories is set, rules using another category are rejected with ErrUndeclaredCategory.
+// Disabled rules are dropped. Rules exceeding the opts.Lint thresholds are logged as warnings.
+// Example code is truncated according to opts.MaxExampleChars without
+// modifying the provided configuration. Client identifiers in opts.Clients are
+// matched case-insensitively.
+func New(cfg *Config, opts *Options) (*Repository, error) {
+	if err := checkRules(cfg, declaredCategories(opts.Categories)); err != nil {
+		return nil, err
+	}
+
+	cfg = enabledRules(cfg)
+
+	if len(*cfg) == 0 {
+		if opts.RequireRules {
+			return nil, ErrNoRules
+		}
+
+		slog.Warn("No rules configured, all queries will return empty results")
+	}
+
+	if opts.trimsExamples() {
+		cfg = trimExamples(cfg)
+	}
+
+	lintRules(cfg, opts.Lint)
+
+	if opts.MaxExampleChars > 0 {
+		cfg = truncateExamples(cfg, opts.MaxExampleChars)
+	}
+
+	clients := make(map[string]ClientFilter, len(opts.Clients))
+	for id, filter := range opts.Clients {
+		clients[strings.ToLower(id)] = filter
+	}
+
 	return &Repository{
-		config: cfg,
+		config:  cfg,
+		clients: clients,
+		lint:    opts.Lint,
+	}, nil
+}
+
+// Problem describes an issue with a rule found by Validate.
+type Problem struct {
+	// Rule is the name of the rule, empty for problems with the rule set as a whole
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Validate runs the checks New applies when loading cfg, without stopping at the
+// first failure. Errors are the problems that make New fail, warnings are the
+// problems New only logs, such as rules exceeding the opts.Lint thresholds.
+// Disabled rules are checked for errors but not linted, as New drops them before linting.
+func Validate(cfg *Config, opts *Options) (errs, warnings []Problem) {
+	names := ruleNames(cfg)
+	categories := declaredCategories(opts.Categories)
+
+	for _, rule := range *cfg {
+		for _, err := range ruleErrors(rule, names, categories) {
+			errs = append(errs, Problem{Rule: rule.Name, Message: err.Error()})
+		}
+	}
+
+	enabled := enabledRules(cfg)
+
+	if len(*enabled) == 0 {
+		if opts.RequireRules {
+			errs = append(errs, Problem{Message: ErrNoRules.Error()})
+		} else {
+			warnings = append(warnings, Problem{Message: "no rules configured"})
+		}
+	}
+
+	if opts.trimsExamples() {
+		enabled = trimExamples(enabled)
+	}
+
+	warnings = append(warnings, lintProblems(enabled, opts.Lint)...)
+
+	return errs, warnings
+}
+
+// ruleNames returns the set of rule names in cfg.
+func ruleNames(cfg *Config) map[string]bool {
+	names := make(map[string]bool, len(*cfg))
+	for _, rule := range *cfg {
+		names[rule.Name] = true
 	}
+
+	return names
 }
 
-// convertRule converts internal Rule to core.Rule.
-// This is an internal helper method that maps between the configuration
-// and domain representations of a rule.
-func (r *Repository) convertRule(rule Rule) core.Rule {
+// declaredCategories returns the declared category names, nil if none are declared.
+func declaredCategories(categories []Category) []string {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	declared := make([]string, 0, len(categories))
+	for _, category := range categories {
+		declared = append(declared, category.Name)
+	}
+
+	return declared
+}
+
+// CheckRules runs the rule checks of New on cfg, except for the category declarations,
+// which only exist in Options. Other repositories holding Rules use it to reject the
+// rules New would reject.
+// Returns the first error New would return for an invalid rule.
+func CheckRules(cfg *Config) error {
+	return checkRules(cfg, nil)
+}
+
+// checkRules returns the first error of the rules in cfg, see ruleErrors.
+func checkRules(cfg *Config, categories []string) error {
+	names := ruleNames(cfg)
+
+	for _, rule := range *cfg {
+		if errs := ruleErrors(rule, names, categories); len(errs) > 0 {
+			return errs[0]
+		}
+	}
+
+	return nil
+}
+
+// ruleErrors returns every reason rule cannot be loaded. Conflicts must reference
+// names in names, and, unless categories is nil, the rule category must be one of
+// categories or nested in one, as matched by core.MatchesCategory.
+func ruleErrors(rule Rule, names map[string]bool, categories []string) []error {
+	var errs []error
+
+	if categories != nil && !matchesAnyCategory(rule.Category, categories) {
+		errs = append(errs, fmt.Errorf("%w %q in rule %q", ErrUndeclaredCategory, rule.Category, rule.Name))
+	}
+
+	if rule.Severity != "" && !core.IsValidSeverity(strings.ToLower(rule.Severity)) {
+		errs = append(errs, fmt.Errorf("%w %q in rule %q", ErrInvalidSeverity, rule.Severity, rule.Name))
+	}
+
+	if _, err := parseUpdatedAt(rule.UpdatedAt); err != nil {
+		errs = append(errs, fmt.Errorf("%w %q in rule %q: %w", ErrInvalidUpdatedAt, rule.UpdatedAt, rule.Name, err))
+	}
+
+	if _, err := core.ParseTemplate(rule.Name, rule.Template); err != nil {
+		errs = append(errs, fmt.Errorf("%w in rule %q: %w", ErrInvalidTemplate, rule.Name, err))
+	}
+
+	for _, other := range rule.ConflictsWith {
+		if !names[other] {
+			errs = append(errs, fmt.Errorf("%w %q in rule %q", ErrUnknownConflict, other, rule.Name))
+		}
+	}
+
+	return errs
+}
+
+// rules returns the configured rules available to the client identified in ctx.
+// Requests without a client identifier, or from clients without a configured
+// filter, get the full rule set.
+func (r *Repository) rules(ctx context.Context) []Rule {
+	filter, ok := r.clients[strings.ToLower(core.ClientIDFromContext(ctx))]
+	if !ok {
+		return *r.config
+	}
+
+	rules := make([]Rule, 0, len(*r.config))
+
+	for _, rule := range *r.config {
+		if filter.allows(rule) {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// ConvertRule converts internal Rule to core.Rule.
+// It maps between the configuration and domain representations of a rule,
+// and expects a rule accepted by CheckRules.
+func ConvertRule(rule Rule) core.Rule {
+	severity := strings.ToLower(rule.Severity)
+	if severity == "" {
+		severity = core.DefaultSeverity
+	}
+
+	updatedAt, _ := parseUpdatedAt(rule.UpdatedAt) // validated in New
+
 	return core.Rule{
-		Name:        rule.Name,
-		Category:    rule.Category,
-		Description: rule.Description,
-		Examples:    convertExamples(rule.Examples),
+		Name:            rule.Name,
+		Category:        rule.Category,
+		Description:     rule.Description,
+		Severity:        severity,
+		Deprecated:      rule.Deprecated,
+		DeprecationNote: rule.DeprecationNote,
+		Language:        rule.Language,
+		Template:        rule.Template,
+		UpdatedAt:       updatedAt,
+		Examples:        convertExamples(rule.Examples),
+		ConflictsWith:   rule.ConflictsWith,
+		Order:           rule.Order,
 	}
 }
 
+// parseUpdatedAt parses an RFC3339 update time; an empty value yields the zero time.
+func parseUpdatedAt(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
 // convertExamples converts internal Examples to core.Examples.
 // This is an internal helper method that maps between the configuration
 // and domain representations of examples.
@@ -70,35 +395,306 @@ func convertExamples(examples []Example) []core.Example {
 		result[i] = core.Example{
 			Description: e.Description,
 			Code:        e.Code,
+			Language:    e.Language,
+			Metadata:    e.Metadata,
+			Order:       e.Order,
 		}
 	}
 
 	return result
 }
 
-// GetCodeStyle returns all rules that match the specified categories.
+// GetCodeStyle returns all rules that match the specified categories and filter.
 // It filters the configuration rules by categories, converting matches to core.Rule format.
+// A category also matches its descendants, e.g. "code" matches "code/concurrency",
+// and the core.AllCategories wildcard matches every rule.
 // Returns error if the context is cancelled.
-func (r *Repository) GetCodeStyle(ctx context.Context, categories []string) ([]core.Rule, error) {
+func (r *Repository) GetCodeStyle(ctx context.Context, categories []string, filter core.Filter) ([]core.Rule, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 		var rules []core.Rule
 
-		// Create a map for faster category lookup
-		categoryMap := make(map[string]bool)
-		for _, cat := range categories {
-			categoryMap[cat] = true
+		for _, rule := range r.rules(ctx) {
+			if Matches(rule, categories, filter) {
+				rules = append(rules, ConvertRule(rule))
+			}
 		}
 
-		for _, rule := range *r.config {
-			// Check if rule matches requested category
-			if categoryMap[rule.Category] {
-				rules = append(rules, r.convertRule(rule))
+		return rules, nil
+	}
+}
+
+// Matches reports whether rule is selected by a GetCodeStyle query for categories
+// and filter, i.e. its category matches one of categories and it satisfies the filter.
+func Matches(rule Rule, categories []string, filter core.Filter) bool {
+	return matchesAnyCategory(rule.Category, categories) && matchesFilter(rule, filter)
+}
+
+// matchesAnyCategory reports whether category is selected by any of the requested
+// categories, which also select their descendants, see core.MatchesCategory.
+func matchesAnyCategory(category string, requested []string) bool {
+	return slices.ContainsFunc(requested, func(req string) bool {
+		return core.MatchesCategory(category, req)
+	})
+}
+
+// GetByNames returns all rules whose name is one of names, in configuration order.
+// Names that match no rule are skipped.
+// Returns error if the context is cancelled.
+func (r *Repository) GetByNames(ctx context.Context, names []string) ([]core.Rule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	nameMap := make(map[string]bool, len(names))
+	for _, name := range names {
+		nameMap[name] = true
+	}
+
+	var rules []core.Rule
+
+	for _, rule := range r.rules(ctx) {
+		if nameMap[rule.Name] {
+			rules = append(rules, ConvertRule(rule))
+		}
+	}
+
+	return rules, nil
+}
+
+// Stats returns aggregate metrics about the configured rules.
+// Returns error if the context is cancelled.
+func (r *Repository) Stats(ctx context.Context) (core.RepoStats, error) {
+	if err := ctx.Err(); err != nil {
+		return core.RepoStats{}, err
+	}
+
+	rules := r.rules(ctx)
+
+	stats := core.RepoStats{
+		TotalRules:       len(rules),
+		RulesPerCategory: make(map[string]int),
+	}
+
+	for _, rule := range rules {
+		stats.RulesPerCategory[rule.Category]++
+
+		if len(rule.Examples) > 0 {
+			stats.RulesWithExamples++
+		}
+	}
+
+	return stats, nil
+}
+
+// Count returns the number of configured rules available to the client in ctx.
+// Returns error if the context is cancelled.
+func (r *Repository) Count(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return len(r.rules(ctx)), nil
+}
+
+// Fingerprint returns the fingerprint of the configured rules available to the client
+// in ctx, see core.FingerprintRules.
+// Returns error if the context is cancelled.
+func (r *Repository) Fingerprint(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	rules := r.rules(ctx)
+
+	converted := make([]core.Rule, 0, len(rules))
+	for _, rule := range rules {
+		converted = append(converted, ConvertRule(rule))
+	}
+
+	return core.FingerprintRules(converted)
+}
+
+// Diagnose returns a snapshot of the configured rules of all clients. Examples exceeding
+// the lint thresholds are reported as problems, see LintOptions.
+func (r *Repository) Diagnose(_ context.Context) core.Diagnostics {
+	categories := make([]string, 0, len(*r.config))
+	for _, rule := range *r.config {
+		categories = append(categories, rule.Category)
+	}
+
+	slices.Sort(categories)
+
+	diag := core.Diagnostics{
+		Type:       "static",
+		Rules:      len(*r.config),
+		Categories: slices.Compact(categories),
+	}
+
+	for _, problem := range lintProblems(r.config, r.lint) {
+		diag.Problems = append(diag.Problems, problem.Rule+": "+problem.Message)
+	}
+
+	return diag
+}
+
+// matchesFilter reports whether the rule satisfies all criteria of the filter.
+func matchesFilter(rule Rule, filter core.Filter) bool {
+	if filter.CodeContains != "" && !examplesContain(rule.Examples, filter.CodeContains) {
+		return false
+	}
+
+	return true
+}
+
+// examplesContain reports whether any example code contains substr, ignoring case and
+// diacritics, see core.NormalizeText.
+func examplesContain(examples []Example, substr string) bool {
+	substr = core.NormalizeText(substr)
+
+	for _, e := range examples {
+		if strings.Contains(core.NormalizeText(e.Code), substr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enabledRules returns the configuration without disabled rules.
+// The provided configuration is returned as is when all rules are enabled.
+func enabledRules(cfg *Config) *Config {
+	if !slices.ContainsFunc(*cfg, func(rule Rule) bool { return !rule.IsEnabled() }) {
+		return cfg
+	}
+
+	enabled := make(Config, 0, len(*cfg))
+
+	for _, rule := range *cfg {
+		if rule.IsEnabled() {
+			enabled = append(enabled, rule)
+		}
+	}
+
+	return &enabled
+}
+
+// lintRules logs a warning for every rule exceeding the lint thresholds.
+// Example sizes are checked before truncation, so oversized sources are reported
+// even when Options.MaxExampleChars hides them from responses.
+func lintRules(cfg *Config, opts LintOptions) {
+	for _, problem := range lintProblems(cfg, opts) {
+		slog.Warn("Rule failed lint check", slog.String("rule", problem.Rule), slog.String("problem", problem.Message))
+	}
+}
+
+// lintProblems returns a problem for every rule exceeding the lint thresholds.
+func lintProblems(cfg *Config, opts LintOptions) []Problem {
+	maxBytes := cmp.Or(opts.MaxExampleBytes, defaultMaxExampleBytes)
+	maxExamples := cmp.Or(opts.MaxExamples, defaultMaxExamples)
+
+	var problems []Problem
+
+	for _, rule := range *cfg {
+		if maxExamples > 0 && len(rule.Examples) > maxExamples {
+			problems = append(problems, Problem{
+				Rule:    rule.Name,
+				Message: fmt.Sprintf("rule has %d examples, more than %d", len(rule.Examples), maxExamples),
+			})
+		}
+
+		if maxBytes <= 0 {
+			continue
+		}
+
+		for i, example := range rule.Examples {
+			if len(example.Code) > maxBytes {
+				problems = append(problems, Problem{
+					Rule:    rule.Name,
+					Message: fmt.Sprintf("example %d is %d bytes, more than %d", i, len(example.Code), maxBytes),
+				})
 			}
 		}
+	}
 
-		return rules, nil
+	return problems
+}
+
+// trimExamples returns a copy of the configuration with example code trimmed, see trimCode.
+func trimExamples(cfg *Config) *Config {
+	trimmed := make(Config, len(*cfg))
+
+	for i, rule := range *cfg {
+		examples := make([]Example, len(rule.Examples))
+
+		for j, e := range rule.Examples {
+			e.Code = trimCode(e.Code)
+			examples[j] = e
+		}
+
+		rule.Examples = examples
+		trimmed[i] = rule
+	}
+
+	return &trimmed
+}
+
+// trimCode removes trailing whitespace from each line of code and drops trailing
+// blank lines, keeping a single final newline if code ended with one. Leading
+// whitespace, and so indentation, is preserved.
+func trimCode(code string) string {
+	var sb strings.Builder
+
+	for line := range strings.Lines(code) {
+		sb.WriteString(strings.TrimRightFunc(line, unicode.IsSpace))
+		sb.WriteByte('\n')
+	}
+
+	trimmed := strings.TrimRight(sb.String(), "\n")
+	if trimmed != "" && strings.HasSuffix(code, "\n") {
+		trimmed += "\n"
+	}
+
+	return trimmed
+}
+
+// truncateExamples returns a copy of the configuration with example code
+// longer than maxChars truncated.
+func truncateExamples(cfg *Config, maxChars int) *Config {
+	truncated := make(Config, len(*cfg))
+
+	for i, rule := range *cfg {
+		examples := make([]Example, len(rule.Examples))
+
+		for j, e := range rule.Examples {
+			e.Code = truncateCode(e.Code, maxChars)
+			examples[j] = e
+		}
+
+		rule.Examples = examples
+		truncated[i] = rule
 	}
+
+	return &truncated
+}
+
+// truncateCode cuts code to at most maxChars characters followed by a truncation marker.
+// The cut is moved back to the last line boundary when the kept part contains one,
+// so that no partial line is returned.
+func truncateCode(code string, maxChars int) string {
+	if utf8.RuneCountInString(code) <= maxChars {
+		return code
+	}
+
+	kept := string([]rune(code)[:maxChars])
+
+	if idx := strings.LastIndex(kept, "\n"); idx >= 0 {
+		kept = kept[:idx+1]
+	} else {
+		kept += "\n"
+	}
+
+	return kept + truncationMarker
 }
@@ -1,8 +1,15 @@
 package static
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ksysoev/mcp-go-tools/pkg/core"
 )
@@ -45,7 +52,11 @@ func TestGetCodeStyle(t *testing.T) {
 	}
 	cfg := &config
 
-	svc := New(cfg)
+	svc, err := New(cfg, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
 	ctx := context.Background()
 
 	tests := []struct {
@@ -63,6 +74,11 @@ func TestGetCodeStyle(t *testing.T) {
 			categories: []string{"testing", "code"},
 			want:       3,
 		},
+		{
+			name:       "all categories wildcard",
+			categories: []string{core.AllCategories},
+			want:       3,
+		},
 		{
 			name:       "no matching rules",
 			categories: []string{"nonexistent"},
@@ -73,7 +89,7 @@ func TestGetCodeStyle(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var rules []core.Rule
-			rules, err := svc.GetCodeStyle(ctx, tt.categories)
+			rules, err := svc.GetCodeStyle(ctx, tt.categories, core.Filter{})
 
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
@@ -87,7 +103,7 @@ func TestGetCodeStyle(t *testing.T) {
 				found := false
 
 				for _, cat := range tt.categories {
-					if rule.Category == cat {
+					if cat == core.AllCategories || rule.Category == cat {
 						found = true
 						break
 					}
@@ -100,3 +116,1106 @@ func TestGetCodeStyle(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCodeStyleCodeContains(t *testing.T) {
+	config := Config{
+		{
+			Name:     "errgroup_rule",
+			Category: "code",
+			Examples: []Example{
+				{Description: "Other", Code: "var x = 1"},
+				{Description: "Errgroup", Code: "eg, ctx := errgroup.WithContext(ctx)"},
+			},
+		},
+		{
+			Name:     "plain_rule",
+			Category: "code",
+			Examples: []Example{
+				{Description: "Plain", Code: "var y = 2"},
+			},
+		},
+		{
+			Name:     "accented_rule",
+			Category: "code",
+			Examples: []Example{
+				{Description: "Accented", Code: `msg := "Café résumé"`},
+			},
+		},
+		{
+			Name:     "no_examples",
+			Category: "code",
+		},
+	}
+
+	svc, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		codeContains string
+		want         []string
+	}{
+		{
+			name:         "no filter",
+			codeContains: "",
+			want:         []string{"errgroup_rule", "plain_rule", "accented_rule", "no_examples"},
+		},
+		{
+			name:         "case-insensitive match",
+			codeContains: "ErrGroup",
+			want:         []string{"errgroup_rule"},
+		},
+		{
+			name:         "accent-insensitive match",
+			codeContains: "CAFE RESUME",
+			want:         []string{"accented_rule"},
+		},
+		{
+			name:         "accented query",
+			codeContains: "résumé",
+			want:         []string{"accented_rule"},
+		},
+		{
+			name:         "no match",
+			codeContains: "sync.Mutex",
+			want:         nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := svc.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{CodeContains: tt.codeContains})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var names []string
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected rules %v, got %v", tt.want, names)
+			}
+		})
+	}
+}
+
+func TestGetCodeStyleCategoryHierarchy(t *testing.T) {
+	config := Config{
+		{Name: "plain", Category: "code"},
+		{Name: "channels", Category: "code/concurrency"},
+		{Name: "wrapping", Category: "code/errors"},
+		{Name: "sentinels", Category: "code/errors/sentinel"},
+		{Name: "generator", Category: "codegen"},
+	}
+
+	svc, err := New(&config, &Options{
+		Clients: map[string]ClientFilter{"team-a": {Categories: []string{"code/errors"}}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		client     string
+		categories []string
+		want       []string
+	}{
+		{
+			name:       "parent matches descendants",
+			categories: []string{"code"},
+			want:       []string{"plain", "channels", "wrapping", "sentinels"},
+		},
+		{
+			name:       "intermediate level",
+			categories: []string{"code/errors"},
+			want:       []string{"wrapping", "sentinels"},
+		},
+		{
+			name:       "leaf exact match",
+			categories: []string{"code/concurrency"},
+			want:       []string{"channels"},
+		},
+		{
+			name:       "prefix without separator does not match",
+			categories: []string{"code/err"},
+			want:       nil,
+		},
+		{
+			name:       "client categories include descendants",
+			client:     "team-a",
+			categories: []string{core.AllCategories},
+			want:       []string{"wrapping", "sentinels"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := core.WithClientID(context.Background(), tt.client)
+
+			rules, err := svc.GetCodeStyle(ctx, tt.categories, core.Filter{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var names []string
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected rules %v, got %v", tt.want, names)
+			}
+		})
+	}
+}
+
+func TestGetCodeStyleExampleMetadata(t *testing.T) {
+	config := Config{
+		{
+			Name:     "metadata_rule",
+			Category: "code",
+			Examples: []Example{
+				{
+					Description: "Anti-pattern",
+					Code:        "panic(err)",
+					Metadata:    map[string]any{"good": false, "tags": "errors"},
+				},
+			},
+		},
+	}
+
+	svc, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := svc.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 1 || len(rules[0].Examples) != 1 {
+		t.Fatalf("Expected 1 rule with 1 example, got %v", rules)
+	}
+
+	if !rules[0].Examples[0].IsAntiPattern() {
+		t.Errorf("Expected example to be an anti-pattern, metadata: %v", rules[0].Examples[0].Metadata)
+	}
+
+	if rules[0].Examples[0].Metadata["tags"] != "errors" {
+		t.Errorf("Expected tags metadata to be preserved, got %v", rules[0].Examples[0].Metadata)
+	}
+}
+
+func TestGetCodeStyleLanguage(t *testing.T) {
+	config := Config{
+		{
+			Name:     "python_rule",
+			Category: "code",
+			Language: "python",
+			Examples: []Example{
+				{Description: "Inherited", Code: "import os"},
+				{Description: "Override", Code: "SELECT 1", Language: "sql", Order: 1},
+			},
+		},
+	}
+
+	svc, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := svc.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 1 || len(rules[0].Examples) != 2 {
+		t.Fatalf("Expected 1 rule with 2 examples, got %v", rules)
+	}
+
+	if rules[0].Language != "python" {
+		t.Errorf("Expected rule language python, got %q", rules[0].Language)
+	}
+
+	if rules[0].Examples[0].Language != "" || rules[0].Examples[1].Language != "sql" {
+		t.Errorf("Expected example languages \"\" and sql, got %q and %q", rules[0].Examples[0].Language, rules[0].Examples[1].Language)
+	}
+
+	if rules[0].Examples[0].Order != 0 || rules[0].Examples[1].Order != 1 {
+		t.Errorf("Expected example orders 0 and 1, got %d and %d", rules[0].Examples[0].Order, rules[0].Examples[1].Order)
+	}
+}
+
+func TestTruncateCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		want     string
+		maxChars int
+	}{
+		{
+			name:     "short code is untouched",
+			code:     "line1\nline2\n",
+			maxChars: 100,
+			want:     "line1\nline2\n",
+		},
+		{
+			name:     "exact length is untouched",
+			code:     "line1\n",
+			maxChars: 6,
+			want:     "line1\n",
+		},
+		{
+			name:     "cut on line boundary",
+			code:     "line1\nline2\nline3\n",
+			maxChars: 9,
+			want:     "line1\n" + truncationMarker,
+		},
+		{
+			name:     "cut mid line without boundary",
+			code:     "a very long single line",
+			maxChars: 6,
+			want:     "a very\n" + truncationMarker,
+		},
+		{
+			name:     "multibyte characters",
+			code:     "héllo wörld",
+			maxChars: 4,
+			want:     "héll\n" + truncationMarker,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateCode(tt.code, tt.maxChars); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTrimCode(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{name: "clean code is untouched", code: "line1\n\tline2\n", want: "line1\n\tline2\n"},
+		{name: "trailing whitespace", code: "a := 1  \nb := 2\t\r\n", want: "a := 1\nb := 2\n"},
+		{name: "trailing blank lines", code: "x\n\n  \n\t\n", want: "x\n"},
+		{name: "internal blank lines and indentation kept", code: "if ok {\n   \n    return\n}\n", want: "if ok {\n\n    return\n}\n"},
+		{name: "no final newline", code: "x  ", want: "x"},
+		{name: "whitespace only", code: "  \n\n", want: ""},
+		{name: "empty", code: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trimCode(tt.code); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewTrimExamples(t *testing.T) {
+	messy := "x := 1  \n\n"
+	config := Config{
+		{Name: "rule", Category: "code", Examples: []Example{{Code: messy}}},
+	}
+	disabled := false
+
+	tests := []struct {
+		opts *Options
+		name string
+		want string
+	}{
+		{name: "default", opts: &Options{}, want: "x := 1\n"},
+		{name: "disabled", opts: &Options{TrimExamples: &disabled}, want: messy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, err := New(&config, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			rules, err := svc.GetByNames(context.Background(), []string{"rule"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := rules[0].Examples[0].Code; got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if config[0].Examples[0].Code != messy {
+		t.Errorf("Expected original config to be unchanged, got %q", config[0].Examples[0].Code)
+	}
+}
+
+func TestNewMaxExampleChars(t *testing.T) {
+	longCode := "line1\nline2\nline3\n"
+	config := Config{
+		{
+			Name:     "long_rule",
+			Category: "code",
+			Examples: []Example{
+				{Description: "Long", Code: longCode},
+				{Description: "Short", Code: "x\n"},
+			},
+		},
+	}
+
+	svc, err := New(&config, &Options{MaxExampleChars: 12})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := svc.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got, want := rules[0].Examples[0].Code, "line1\nline2\n"+truncationMarker; got != want {
+		t.Errorf("Expected truncated code %q, got %q", want, got)
+	}
+
+	if got := rules[0].Examples[1].Code; got != "x\n" {
+		t.Errorf("Expected untruncated code, got %q", got)
+	}
+
+	if config[0].Examples[0].Code != longCode {
+		t.Errorf("Expected original config to be unchanged, got %q", config[0].Examples[0].Code)
+	}
+}
+
+func TestRuleSeverity(t *testing.T) {
+	config := Config{
+		{Name: "default_rule", Category: "code"},
+		{Name: "must_rule", Category: "code", Severity: "MUST"},
+	}
+
+	svc, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := svc.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if rules[0].Severity != core.SeverityShould {
+		t.Errorf("Expected default severity %q, got %q", core.SeverityShould, rules[0].Severity)
+	}
+
+	if rules[1].Severity != core.SeverityMust {
+		t.Errorf("Expected normalized severity %q, got %q", core.SeverityMust, rules[1].Severity)
+	}
+}
+
+func TestRuleUpdatedAt(t *testing.T) {
+	config := Config{
+		{Name: "rule1", Category: "code", UpdatedAt: "2024-01-02T15:04:05+02:00"},
+		{Name: "rule2", Category: "code"},
+	}
+
+	svc, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := svc.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := time.Date(2024, 1, 2, 13, 4, 5, 0, time.UTC)
+	if !rules[0].UpdatedAt.Equal(want) {
+		t.Errorf("Expected update time %v, got %v", want, rules[0].UpdatedAt)
+	}
+
+	if !rules[1].UpdatedAt.IsZero() {
+		t.Errorf("Expected zero update time, got %v", rules[1].UpdatedAt)
+	}
+}
+
+func TestNewInvalidUpdatedAt(t *testing.T) {
+	config := Config{
+		{Name: "rule1", Category: "code", UpdatedAt: "yesterday"},
+	}
+
+	repo, err := New(&config, &Options{})
+	if !errors.Is(err, ErrInvalidUpdatedAt) {
+		t.Fatalf("Expected error %v, got %v", ErrInvalidUpdatedAt, err)
+	}
+
+	if repo != nil {
+		t.Errorf("Expected nil repository, got %v", repo)
+	}
+}
+
+func TestRuleTemplate(t *testing.T) {
+	config := Config{
+		{Name: "scaffold", Category: "template", Template: "package {{.pkg}}\n"},
+	}
+
+	repo, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := repo.GetByNames(context.Background(), []string{"scaffold"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 1 || rules[0].Template != "package {{.pkg}}\n" {
+		t.Errorf("Expected rule with template, got %v", rules)
+	}
+}
+
+func TestNewInvalidTemplate(t *testing.T) {
+	config := Config{
+		{Name: "scaffold", Category: "template", Template: "package {{.pkg"},
+	}
+
+	repo, err := New(&config, &Options{})
+	if !errors.Is(err, ErrInvalidTemplate) {
+		t.Fatalf("Expected error %v, got %v", ErrInvalidTemplate, err)
+	}
+
+	if repo != nil {
+		t.Errorf("Expected nil repository, got %v", repo)
+	}
+}
+
+func TestRuleEnabled(t *testing.T) {
+	enabled, disabled := true, false
+
+	config := Config{
+		{Name: "default_rule", Category: "code"},
+		{Name: "enabled_rule", Category: "code", Enabled: &enabled},
+		{Name: "disabled_rule", Category: "code", Enabled: &disabled},
+	}
+
+	svc, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := svc.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 2 || rules[0].Name != "default_rule" || rules[1].Name != "enabled_rule" {
+		t.Errorf("Expected default_rule and enabled_rule, got %v", rules)
+	}
+
+	byName, err := svc.GetByNames(context.Background(), []string{"disabled_rule"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(byName) != 0 {
+		t.Errorf("Expected disabled rule to be hidden, got %v", byName)
+	}
+
+	if len(config) != 3 {
+		t.Errorf("Expected configuration to be left unchanged, got %d rules", len(config))
+	}
+}
+
+func TestNewInvalidSeverity(t *testing.T) {
+	config := Config{
+		{Name: "bad_rule", Category: "code", Severity: "critical"},
+	}
+
+	repo, err := New(&config, &Options{})
+
+	if !errors.Is(err, ErrInvalidSeverity) {
+		t.Fatalf("Expected error %v, got %v", ErrInvalidSeverity, err)
+	}
+
+	if repo != nil {
+		t.Errorf("Expected nil repository, got %v", repo)
+	}
+}
+
+func TestStats(t *testing.T) {
+	config := Config{
+		{Name: "rule1", Category: "code", Examples: []Example{{Code: "x"}}},
+		{Name: "rule2", Category: "code"},
+		{Name: "rule3", Category: "testing", Examples: []Example{{Code: "y"}}},
+	}
+
+	svc, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stats, err := svc.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if stats.TotalRules != 3 {
+		t.Errorf("Expected 3 rules, got %d", stats.TotalRules)
+	}
+
+	if stats.RulesWithExamples != 2 {
+		t.Errorf("Expected 2 rules with examples, got %d", stats.RulesWithExamples)
+	}
+
+	if stats.RulesPerCategory["code"] != 2 || stats.RulesPerCategory["testing"] != 1 {
+		t.Errorf("Unexpected rules per category: %v", stats.RulesPerCategory)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := svc.Stats(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGetByNames(t *testing.T) {
+	config := Config{
+		{Name: "rule1", Category: "code"},
+		{Name: "rule2", Category: "testing"},
+		{Name: "rule3", Category: "code"},
+	}
+
+	svc, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := svc.GetByNames(context.Background(), []string{"rule3", "missing", "rule1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+
+	if rules[0].Name != "rule1" || rules[1].Name != "rule3" {
+		t.Errorf("Expected rules [rule1 rule3], got [%s %s]", rules[0].Name, rules[1].Name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := svc.GetByNames(ctx, []string{"rule1"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestClientFilters(t *testing.T) {
+	config := Config{
+		{Name: "rule1", Category: "code"},
+		{Name: "rule2", Category: "testing"},
+		{Name: "rule3", Category: "code"},
+	}
+
+	opts := Options{
+		Clients: map[string]ClientFilter{
+			"Team-A": {Categories: []string{"code"}},
+			"team-b": {Rules: []string{"rule2", "rule3"}},
+		},
+	}
+
+	svc, err := New(&config, &opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		client string
+		want   []string
+	}{
+		{name: "no client", client: "", want: []string{"rule1", "rule2", "rule3"}},
+		{name: "unknown client", client: "team-c", want: []string{"rule1", "rule2", "rule3"}},
+		{name: "category filter", client: "team-a", want: []string{"rule1", "rule3"}},
+		{name: "rule filter", client: "TEAM-B", want: []string{"rule2", "rule3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := core.WithClientID(context.Background(), tt.client)
+
+			rules, err := svc.GetCodeStyle(ctx, []string{core.AllCategories}, core.Filter{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var names []string
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Expected rules %v, got %v", tt.want, names)
+			}
+
+			byName, err := svc.GetByNames(ctx, []string{"rule1", "rule2", "rule3"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(byName) != len(tt.want) {
+				t.Errorf("Expected %d rules by name, got %d", len(tt.want), len(byName))
+			}
+
+			stats, err := svc.Stats(ctx)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if stats.TotalRules != len(tt.want) {
+				t.Errorf("Expected %d total rules, got %d", len(tt.want), stats.TotalRules)
+			}
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	config := Config{
+		{Name: "rule1", Category: "code"},
+		{Name: "rule2", Category: "testing"},
+	}
+
+	svc, err := New(&config, &Options{
+		Clients: map[string]ClientFilter{"team-a": {Categories: []string{"code"}}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	count, err := svc.Count(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("Expected 2 rules, got %d", count)
+	}
+
+	count, err = svc.Count(core.WithClientID(context.Background(), "team-a"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("Expected 1 rule for client, got %d", count)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := svc.Count(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	config := Config{
+		{Name: "rule1", Category: "code", Description: "First"},
+		{Name: "rule2", Category: "testing", Description: "Second"},
+	}
+	reordered := Config{config[1], config[0]}
+
+	svc, err := New(&config, &Options{
+		Clients: map[string]ClientFilter{"team-a": {Categories: []string{"code"}}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	other, err := New(&reordered, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fingerprint, err := svc.Fingerprint(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	otherFingerprint, err := other.Fingerprint(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fingerprint != otherFingerprint {
+		t.Errorf("Expected equal fingerprints regardless of rule order, got %q and %q", fingerprint, otherFingerprint)
+	}
+
+	clientFingerprint, err := svc.Fingerprint(core.WithClientID(context.Background(), "team-a"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if clientFingerprint == fingerprint {
+		t.Errorf("Expected the fingerprint of the client subset to differ, got %q", clientFingerprint)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := svc.Fingerprint(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	config := Config{
+		{Name: "rule1", Category: "testing"},
+		{Name: "rule2", Category: "code", Examples: []Example{{Code: "a"}, {Code: "b"}}},
+		{Name: "rule3", Category: "code"},
+	}
+
+	svc, err := New(&config, &Options{
+		Clients: map[string]ClientFilter{"team-a": {Categories: []string{"code"}}},
+		Lint:    LintOptions{MaxExamples: 1},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	diag := svc.Diagnose(core.WithClientID(context.Background(), "team-a"))
+
+	if diag.Type != "static" {
+		t.Errorf("Expected type static, got %q", diag.Type)
+	}
+
+	if diag.Rules != 3 {
+		t.Errorf("Expected 3 rules regardless of client, got %d", diag.Rules)
+	}
+
+	if strings.Join(diag.Categories, ",") != "code,testing" {
+		t.Errorf("Expected categories [code testing], got %v", diag.Categories)
+	}
+
+	want := "rule2: rule has 2 examples, more than 1"
+	if len(diag.Problems) != 1 || diag.Problems[0] != want {
+		t.Errorf("Expected problems [%s], got %v", want, diag.Problems)
+	}
+}
+
+func TestCanonicalOrderIndependentOfConfigOrder(t *testing.T) {
+	config := Config{
+		{Name: "b_rule", Category: "testing"},
+		{Name: "z_rule", Category: "code"},
+		{Name: "a_rule", Category: "testing"},
+		{Name: "a_rule", Category: "code"},
+	}
+
+	reversed := make(Config, len(config))
+	for i, rule := range config {
+		reversed[len(config)-1-i] = rule
+	}
+
+	var results [][]string
+
+	for _, cfg := range []Config{config, reversed} {
+		repo, err := New(&cfg, &Options{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		rules, err := core.New(repo).GetCodeStyle(context.Background(), []string{core.AllCategories}, core.Filter{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		names := make([]string, 0, len(rules))
+		for _, rule := range rules {
+			names = append(names, rule.Category+"/"+rule.Name)
+		}
+
+		results = append(results, names)
+	}
+
+	want := "code/a_rule,code/z_rule,testing/a_rule,testing/b_rule"
+
+	for _, names := range results {
+		if got := strings.Join(names, ","); got != want {
+			t.Errorf("Expected order %s, got %s", want, got)
+		}
+	}
+}
+
+func TestRuleDeprecation(t *testing.T) {
+	config := Config{
+		{Name: "old_rule", Category: "code", Deprecated: true, DeprecationNote: "use new_rule"},
+	}
+
+	svc, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := svc.GetCodeStyle(context.Background(), []string{"code"}, core.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 1 || !rules[0].Deprecated || rules[0].DeprecationNote != "use new_rule" {
+		t.Errorf("Expected deprecation to be preserved, got %+v", rules)
+	}
+}
+
+func TestNewEmptyRules(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		wantErr      error
+		name         string
+		rules        Config
+		wantWarning  bool
+		requireRules bool
+	}{
+		{
+			name:         "warns when rules are not required",
+			requireRules: false,
+			wantWarning:  true,
+		},
+		{
+			name:         "fails when rules are required",
+			requireRules: true,
+			wantErr:      ErrNoRules,
+		},
+		{
+			name:         "warns when all rules are disabled",
+			rules:        Config{{Name: "old_rule", Category: "code", Enabled: &disabled}},
+			requireRules: false,
+			wantWarning:  true,
+		},
+		{
+			name:         "fails when all rules are disabled and rules are required",
+			rules:        Config{{Name: "old_rule", Category: "code", Enabled: &disabled}},
+			requireRules: true,
+			wantErr:      ErrNoRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			defaultLogger := slog.Default()
+			slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+			t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+			repo, err := New(&tt.rules, &Options{RequireRules: tt.requireRules})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+				}
+
+				if repo != nil {
+					t.Errorf("Expected nil repository, got %v", repo)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if repo == nil {
+				t.Fatal("Expected repository, got nil")
+			}
+
+			if tt.wantWarning && !strings.Contains(buf.String(), "level=WARN") {
+				t.Errorf("Expected warning to be logged, got %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestNewLintWarnings(t *testing.T) {
+	config := Config{
+		{Name: "big_example", Category: "code", Examples: []Example{{Code: strings.Repeat("x", 20)}}},
+		{Name: "many_examples", Category: "code", Examples: []Example{{Code: "a"}, {Code: "b"}, {Code: "c"}}},
+		{Name: "small_rule", Category: "code", Examples: []Example{{Code: "ok"}}},
+	}
+
+	tests := []struct {
+		name         string
+		wantWarnings []string
+		lint         LintOptions
+	}{
+		{
+			name:         "thresholds exceeded",
+			lint:         LintOptions{MaxExampleBytes: 10, MaxExamples: 2},
+			wantWarnings: []string{"rule=big_example", "rule=many_examples"},
+		},
+		{
+			name: "defaults are not exceeded",
+			lint: LintOptions{},
+		},
+		{
+			name: "checks disabled",
+			lint: LintOptions{MaxExampleBytes: -1, MaxExamples: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			defaultLogger := slog.Default()
+			slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+			t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+			if _, err := New(&config, &Options{Lint: tt.lint}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			output := buf.String()
+
+			if got := strings.Count(output, "level=WARN"); got != len(tt.wantWarnings) {
+				t.Errorf("Expected %d warnings, got %d: %q", len(tt.wantWarnings), got, output)
+			}
+
+			for _, want := range tt.wantWarnings {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected warning for %q, got %q", want, output)
+				}
+			}
+
+			if strings.Contains(output, "rule=small_rule") {
+				t.Errorf("Unexpected warning for small_rule: %q", output)
+			}
+		})
+	}
+}
+
+func TestNewConflictsWith(t *testing.T) {
+	config := Config{
+		{Name: "early_return", Category: "code", ConflictsWith: []string{"single_exit"}},
+		{Name: "single_exit", Category: "code"},
+	}
+
+	svc, err := New(&config, &Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules, err := svc.GetByNames(context.Background(), []string{"early_return"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rules) != 1 || len(rules[0].ConflictsWith) != 1 || rules[0].ConflictsWith[0] != "single_exit" {
+		t.Errorf("Expected rule conflicting with single_exit, got %v", rules)
+	}
+}
+
+func TestNewUnknownConflict(t *testing.T) {
+	config := Config{
+		{Name: "early_return", Category: "code", ConflictsWith: []string{"missing_rule"}},
+	}
+
+	repo, err := New(&config, &Options{})
+	if !errors.Is(err, ErrUnknownConflict) {
+		t.Fatalf("Expected error %v, got %v", ErrUnknownConflict, err)
+	}
+
+	if repo != nil {
+		t.Errorf("Expected nil repository, got %v", repo)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	disabled := false
+
+	config := Config{
+		{Name: "bad_severity", Category: "code", Severity: "critical", UpdatedAt: "yesterday"},
+		{Name: "many_examples", Category: "code", Examples: []Example{{Code: "a"}, {Code: "b"}}},
+		{Name: "disabled", Category: "code", Enabled: &disabled, Examples: []Example{{Code: "a"}, {Code: "b"}}},
+	}
+
+	errs, warnings := Validate(&config, &Options{Lint: LintOptions{MaxExamples: 1}})
+
+	if len(errs) != 2 || errs[0].Rule != "bad_severity" || errs[1].Rule != "bad_severity" {
+		t.Errorf("Expected two errors for bad_severity, got %v", errs)
+	}
+
+	if len(warnings) != 1 || warnings[0].Rule != "many_examples" {
+		t.Errorf("Expected one warning for many_examples, got %v", warnings)
+	}
+
+	errs, warnings = Validate(&Config{}, &Options{RequireRules: true})
+	if len(errs) != 1 || errs[0].Message != ErrNoRules.Error() || len(warnings) != 0 {
+		t.Errorf("Expected no rules error, got errors %v and warnings %v", errs, warnings)
+	}
+
+	errs, warnings = Validate(&Config{{Name: "disabled", Category: "code", Enabled: &disabled}}, &Options{RequireRules: true})
+	if len(errs) != 1 || errs[0].Message != ErrNoRules.Error() || len(warnings) != 0 {
+		t.Errorf("Expected no rules error for all rules disabled, got errors %v and warnings %v", errs, warnings)
+	}
+}
+
+func TestNewDeclaredCategories(t *testing.T) {
+	categories := []Category{
+		{Name: "code", DisplayName: "Code", Description: "Code organization"},
+		{Name: "code/errors"},
+		{Name: "testing"},
+	}
+
+	config := Config{
+		{Name: "early_return", Category: "code"},
+		{Name: "error_wrapping", Category: "code/errors"},
+	}
+
+	if _, err := New(&config, &Options{Categories: categories}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Categories nested in a declared one are declared too
+	nested := append(slices.Clone(config), Rule{Name: "mutex_naming", Category: "code/concurrency"})
+	if _, err := New(&nested, &Options{Categories: categories}); err != nil {
+		t.Errorf("Expected nested category to be accepted, got %v", err)
+	}
+
+	// Declaring a category does not declare its parent
+	parent := Config{{Name: "benchmarks", Category: "performance"}}
+	if _, err := New(&parent, &Options{Categories: []Category{{Name: "performance/cpu"}}}); !errors.Is(err, ErrUndeclaredCategory) {
+		t.Errorf("Expected error %v for the parent of a declared category, got %v", ErrUndeclaredCategory, err)
+	}
+
+	config = append(config, Rule{Name: "table_tests", Category: "tesitng"})
+
+	repo, err := New(&config, &Options{Categories: categories})
+	if !errors.Is(err, ErrUndeclaredCategory) {
+		t.Fatalf("Expected error %v, got %v", ErrUndeclaredCategory, err)
+	}
+
+	if repo != nil {
+		t.Errorf("Expected nil repository, got %v", repo)
+	}
+
+	if _, err := New(&config, &Options{}); err != nil {
+		t.Errorf("Expected any category to be accepted without declared categories, got %v", err)
+	}
+}